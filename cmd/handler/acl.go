@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) grant(c echo.Context) error {
+	key := c.Param("key")
+	permission, err := model.ParsePermission(c.FormValue("permission"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	err = h.manager.Grant(h.context(c), model.Key(key), c.Param("subject"), permission)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) revoke(c echo.Context) error {
+	key := c.Param("key")
+	err := h.manager.Revoke(h.context(c), model.Key(key), c.Param("subject"))
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+// explain tells why the principal described by the query can or can't
+// access a question: its name, its role, reader by default, and its groups
+// separated by commas. The role and the groups of a user default to its
+// own.
+func (h *handler) explain(c echo.Context) error {
+	key := c.Param("key")
+	p := model.Principal{Name: c.QueryParam("name"), Role: model.RoleReader}
+	if u, err := h.manager.User(h.context(c), p.Name); err == nil {
+		p = u.Principal()
+	}
+	if v := c.QueryParam("role"); v != "" {
+		role, err := model.ParseRole(v)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		p.Role = role
+	}
+	if c.QueryParams().Has("groups") {
+		p.Groups = nil
+		for _, g := range strings.Split(c.QueryParam("groups"), ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				p.Groups = append(p.Groups, g)
+			}
+		}
+	}
+	l, err := h.manager.Explain(h.context(c), model.Key(key), p)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.JSON(http.StatusOK, struct {
+		Principal model.Principal `json:"principal"`
+		Access    []model.Access  `json:"access"`
+	}{p, l})
+}
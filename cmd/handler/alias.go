@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) addAlias(c echo.Context) error {
+	key := c.Param("key")
+	alias := c.FormValue("alias")
+	err := h.manager.AddAlias(h.context(c), model.Key(key), model.Key(alias))
+	if _, ok := movedTo(err); ok || errors.Is(err, derrors.Conflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) removeAlias(c echo.Context) error {
+	key := c.Param("key")
+	alias := c.Param("alias")
+	err := h.manager.RemoveAlias(h.context(c), model.Key(key), model.Key(alias))
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
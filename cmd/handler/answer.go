@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type answerResponse struct {
+	ID       string      `json:"id"`
+	Author   string      `json:"author"`
+	Value    model.Value `json:"value"`
+	Votes    int         `json:"votes"`
+	Accepted bool        `json:"accepted"`
+}
+
+func (r *answerResponse) Marshal(a model.Answer, accepted bool) {
+	r.ID = a.ID.String()
+	r.Author = a.Author
+	r.Value = a.Value
+	r.Votes = a.Votes
+	r.Accepted = accepted
+}
+
+func (h *handler) answers(c echo.Context) error {
+	key := c.Param("key")
+	q, err := h.manager.Get(h.context(c), model.Key(key))
+	if to, ok := movedTo(err); ok {
+		return redirect(c, questionsPath(c)+url.PathEscape(string(to))+"/answers")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	var l = make([]answerResponse, len(q.Answers))
+	for i, a := range q.Answers {
+		l[i].Marshal(a, a.ID.String() == q.Accepted.String())
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+func (h *handler) postAnswer(c echo.Context) error {
+	key := c.Param("key")
+	value := c.FormValue("value")
+	a, err := h.manager.AddAnswer(h.context(c), model.Key(key), model.Value(value))
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	var rsp answerResponse
+	rsp.Marshal(a, false)
+	return c.JSON(http.StatusCreated, rsp)
+}
+
+func (h *handler) putAnswer(c echo.Context) error {
+	return h.changeAnswer(c, func(key model.Key, id utils.ID) error {
+		return h.manager.EditAnswer(h.context(c), key, id, model.Value(c.FormValue("value")))
+	})
+}
+
+func (h *handler) deleteAnswer(c echo.Context) error {
+	return h.changeAnswer(c, func(key model.Key, id utils.ID) error {
+		return h.manager.RemoveAnswer(h.context(c), key, id)
+	})
+}
+
+// vote records the vote form value, 1, -1 or 0 to withdraw a vote.
+func (h *handler) vote(c echo.Context) error {
+	vote, err := strconv.Atoi(c.FormValue("vote"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid vote, expected 1, -1 or 0")
+	}
+	return h.changeAnswer(c, func(key model.Key, id utils.ID) error {
+		return h.manager.Vote(h.context(c), key, id, vote)
+	})
+}
+
+func (h *handler) acceptAnswer(c echo.Context) error {
+	return h.changeAnswer(c, func(key model.Key, id utils.ID) error {
+		return h.manager.AcceptAnswer(h.context(c), key, id)
+	})
+}
+
+// changeAnswer applies change to the answer identified by the id parameter of
+// the question at the key parameter.
+func (h *handler) changeAnswer(c echo.Context, change func(key model.Key, id utils.ID) error) error {
+	id, err := utils.ParseID(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+	err = change(model.Key(c.Param("key")), id)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
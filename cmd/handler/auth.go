@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerAPIKey          = "X-API-Key"
+	headerWWWAuthenticate = "WWW-Authenticate"
+
+	// principalKey is the key of the principal in the echo context.
+	principalKey = "principal"
+)
+
+// WithJWTSecret accepts the JWTs signed with HS256 by secret.
+func WithJWTSecret(secret []byte) Option {
+	return func(h *handler) {
+		h.jwtSecret = secret
+	}
+}
+
+// WithJWTPublicKey accepts the JWTs signed with RS256 by the private key of
+// key.
+func WithJWTPublicKey(key *rsa.PublicKey) Option {
+	return func(h *handler) {
+		h.jwtPublicKey = key
+	}
+}
+
+// WithAnonymousRole grants role in the default tenant to the requests
+// without credentials. They are refused by default, and always in the other
+// tenants.
+func WithAnonymousRole(role model.Role) Option {
+	return func(h *handler) {
+		h.anonymousRole = role
+	}
+}
+
+// claims are the claims of the JWTs, the subject names the principal. The
+// tokens without tenant are bound to the default one.
+type claims struct {
+	Role   model.Role `json:"role"`
+	Groups []string   `json:"groups"`
+	Tenant string     `json:"tenant,omitempty"`
+	// PasswordGeneration is the generation of the password of the user a
+	// login token was issued to.
+	PasswordGeneration int `json:"pwg,omitempty"`
+	jwt.StandardClaims
+}
+
+// require authenticates the requests and refuses the ones whose principal
+// isn't granted role, or isn't bound to the tenant of the route.
+func (h *handler) require(role model.Role) echo.MiddlewareFunc {
+	return h.requireIn(role, func(c echo.Context) string { return c.Param("tenant") })
+}
+
+// requireAllTenants is like require for the routes spanning every tenant,
+// only open to the principals bound to every tenant.
+func (h *handler) requireAllTenants(role model.Role) echo.MiddlewareFunc {
+	return h.requireIn(role, func(echo.Context) string { return model.AllTenants })
+}
+
+// requireIn is like require for the tenant returned by tenant.
+func (h *handler) requireIn(role model.Role, tenant func(c echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p, err := h.authenticate(c)
+			t := tenant(c)
+			if err == nil && p.Name == "" && (!p.Role.Allows(role) || !p.InTenant(t)) {
+				err = errors.New("missing credentials")
+			}
+			if err != nil {
+				c.Response().Header().Set(headerWWWAuthenticate, `Bearer realm="answer.io"`)
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+			if !p.Role.Allows(role) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("role %s required", role))
+			}
+			if !p.InTenant(t) {
+				if t == "" {
+					t = model.DefaultTenant
+				}
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("not allowed in tenant %s", t))
+			}
+			c.Set(principalKey, p)
+			return next(c)
+		}
+	}
+}
+
+// authenticate returns the principal of the credentials of the request,
+// from its X-API-Key header or its bearer token.
+func (h *handler) authenticate(c echo.Context) (model.Principal, error) {
+	token := c.Request().Header.Get(headerAPIKey)
+	if token == "" {
+		auth := c.Request().Header.Get(echo.HeaderAuthorization)
+		if auth != "" {
+			scheme, credentials, ok := strings.Cut(auth, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") {
+				return model.Principal{}, errors.New("invalid authorization, expected a bearer token")
+			}
+			token = strings.TrimSpace(credentials)
+		}
+	}
+	switch {
+	case token == "" && h.anonymousRole != "":
+		return model.Principal{Role: h.anonymousRole, Tenant: model.DefaultTenant}, nil
+	case token == "":
+		return model.Principal{}, errors.New("missing credentials")
+	case strings.HasPrefix(token, model.APIKeyPrefix):
+		p, err := h.manager.Authenticate(c.Request().Context(), token)
+		if err != nil {
+			return model.Principal{}, errors.New("invalid API key")
+		}
+		return p, nil
+	default:
+		return h.parseJWT(c, token)
+	}
+}
+
+// parseJWT returns the principal of a JWT signed with one of the keys of
+// the handler, which must expire. The JWTs issued on login name a user,
+// whose current role and groups apply, and are revoked by a change of its
+// password. The claims are checked against utils.Clock.
+func (h *handler) parseJWT(c echo.Context, token string) (model.Principal, error) {
+	var cl claims
+	parser := jwt.Parser{SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(token, &cl, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method {
+		case jwt.SigningMethodHS256:
+			if h.jwtSecret != nil {
+				return h.jwtSecret, nil
+			}
+		case jwt.SigningMethodRS256:
+			if h.jwtPublicKey != nil {
+				return h.jwtPublicKey, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method %s", t.Header["alg"])
+	})
+	if err != nil {
+		return model.Principal{}, fmt.Errorf("invalid token: %w", err)
+	}
+	now := utils.Clock().Unix()
+	switch {
+	case cl.ExpiresAt == 0:
+		return model.Principal{}, errors.New("invalid token: missing expiration")
+	case !cl.VerifyExpiresAt(now, true):
+		return model.Principal{}, errors.New("invalid token: token is expired")
+	case !cl.VerifyNotBefore(now, false) || !cl.VerifyIssuedAt(now, false):
+		return model.Principal{}, errors.New("invalid token: token used before issued")
+	}
+	if cl.Subject == "" {
+		return model.Principal{}, errors.New("invalid token: missing subject")
+	}
+	if cl.Issuer == tokenIssuer {
+		u, err := h.manager.User(c.Request().Context(), cl.Subject)
+		if err != nil {
+			return model.Principal{}, errors.New("invalid token: unknown user")
+		}
+		if cl.PasswordGeneration != u.PasswordGeneration {
+			return model.Principal{}, errors.New("invalid token: password changed since")
+		}
+		return u.Principal(), nil
+	}
+	if _, err := model.ParseRole(string(cl.Role)); err != nil {
+		return model.Principal{}, fmt.Errorf("invalid token: %w", err)
+	}
+	return model.Principal{Name: cl.Subject, Role: cl.Role, Groups: cl.Groups, Tenant: cl.Tenant}, nil
+}
+
+// principal returns the principal the request is authenticated as.
+func principal(c echo.Context) (model.Principal, bool) {
+	p, ok := c.Get(principalKey).(model.Principal)
+	return p, ok
+}
+
+type apiKeyResponse struct {
+	model.APIKey
+	// Key is only returned when it is created.
+	Key string `json:"key,omitempty"`
+}
+
+func (h *handler) apiKeys(c echo.Context) error {
+	l, err := h.manager.APIKeys(h.context(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rsp := make([]apiKeyResponse, len(l))
+	for i, k := range l {
+		rsp[i].APIKey = k
+	}
+	return c.JSON(http.StatusOK, rsp)
+}
+
+func (h *handler) createAPIKey(c echo.Context) error {
+	name := c.FormValue("name")
+	role, err := model.ParseRole(c.FormValue("role"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	k, key, err := h.manager.CreateAPIKey(h.context(c), name, role, c.FormValue("tenant"))
+	if errors.Is(err, derrors.Conflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusCreated, apiKeyResponse{APIKey: k, Key: key})
+}
+
+func (h *handler) revokeAPIKey(c echo.Context) error {
+	err := h.manager.RevokeAPIKey(h.context(c), c.Param("name"))
+	if errors.Is(err, derrors.NotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"answer.io/pkg/model"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequire(t *testing.T) {
+	secret := []byte("test secret")
+	token := func(role model.Role) string {
+		cl := claims{Role: role, StandardClaims: jwt.StandardClaims{
+			Subject:   "alice",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, cl).SignedString(secret)
+		if err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		return token
+	}
+	s := newTestServer(t, WithJWTSecret(secret))
+	anonymous := newTestServer(t, WithJWTSecret(secret), WithAnonymousRole(model.RoleReader))
+	for _, srv := range []*testServer{s, anonymous} {
+		if _, err := srv.manager.New(context.Background(), "refunds", "Refunds take 5 days"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	acme := model.NewContextWithTenant(context.Background(), "acme")
+	if err := anonymous.manager.CreateTenant(context.Background(), "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := anonymous.manager.New(acme, "refunds", "Refunds take a week"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name     string
+		s        *testServer
+		method   string
+		path     string
+		header   http.Header
+		wantCode int
+	}{
+		{
+			name:     "missing credentials",
+			s:        s,
+			method:   http.MethodGet,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "invalid API key",
+			s:        s,
+			method:   http.MethodGet,
+			header:   http.Header{headerAPIKey: {model.APIKeyPrefix + "unknown"}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "API key",
+			s:        s,
+			method:   http.MethodGet,
+			header:   http.Header{headerAPIKey: {s.keys[model.RoleReader]}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "API key as bearer token",
+			s:        s,
+			method:   http.MethodGet,
+			header:   http.Header{echo.HeaderAuthorization: {"Bearer " + s.keys[model.RoleReader]}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "API key without the role",
+			s:        s,
+			method:   http.MethodPost,
+			header:   http.Header{headerAPIKey: {s.keys[model.RoleReader]}},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "JWT",
+			s:        s,
+			method:   http.MethodPost,
+			header:   http.Header{echo.HeaderAuthorization: {"Bearer " + token(model.RoleEditor)}},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "JWT without the role",
+			s:        s,
+			method:   http.MethodPost,
+			header:   http.Header{echo.HeaderAuthorization: {"Bearer " + token(model.RoleReader)}},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "invalid JWT",
+			s:        s,
+			method:   http.MethodGet,
+			header:   http.Header{echo.HeaderAuthorization: {"Bearer not-a-token"}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "other scheme",
+			s:        s,
+			method:   http.MethodGet,
+			header:   http.Header{echo.HeaderAuthorization: {"Basic YWxpY2U6c2VjcmV0"}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "anonymous role",
+			s:        anonymous,
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "anonymous without the role",
+			s:        anonymous,
+			method:   http.MethodPost,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "anonymous in another tenant",
+			s:        anonymous,
+			method:   http.MethodGet,
+			path:     "/t/acme/questions/refunds",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			path, form := "/questions/refunds", url.Values(nil)
+			if tt.method == http.MethodPost {
+				path, form = "/questions/refunds/tags", url.Values{"tag": {"billing"}}
+			}
+			if tt.path != "" {
+				path = tt.path
+			}
+			rec := tt.s.do(tt.method, path, "", form, tt.header)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("got = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if got := rec.Header().Get(headerWWWAuthenticate); (got != "") != (tt.wantCode == http.StatusUnauthorized) {
+				t.Errorf("got %s header %q for status %d", headerWWWAuthenticate, got, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRequireTenant(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	if err := s.manager.CreateTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.manager.New(model.NewContextWithTenant(ctx, "acme"), "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	keys := map[string]string{}
+	for _, k := range []struct {
+		name   string
+		role   model.Role
+		tenant string
+	}{
+		{"acme-reader", model.RoleReader, "acme"},
+		{"acme-admin", model.RoleAdmin, "acme"},
+		{"ops", model.RoleAdmin, model.AllTenants},
+	} {
+		_, key, err := s.manager.CreateAPIKey(ctx, k.name, k.role, k.tenant)
+		if err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		keys[k.name] = key
+	}
+	keys["reader"] = s.keys[model.RoleReader]
+
+	var testCases = []struct {
+		name     string
+		key      string
+		path     string
+		wantCode int
+	}{
+		{
+			name:     "key of the tenant",
+			key:      "acme-reader",
+			path:     "/t/acme/questions/refunds",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "key of the default tenant",
+			key:      "reader",
+			path:     "/t/acme/questions/refunds",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "key of another tenant",
+			key:      "acme-reader",
+			path:     "/questions/",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "key of every tenant",
+			key:      "ops",
+			path:     "/t/acme/questions/refunds",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "tenant admin on the routes of every tenant",
+			key:      "acme-admin",
+			path:     "/admin/tenants",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "admin of every tenant",
+			key:      "ops",
+			path:     "/admin/tenants",
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{headerAPIKey: {keys[tt.key]}}
+			rec := s.do(http.MethodGet, tt.path, "", nil, header)
+			if rec.Code != tt.wantCode {
+				t.Errorf("got = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+}
+
+func TestParseJWT(t *testing.T) {
+	secret := []byte("test secret")
+	s := newTestServer(t)
+	h := &handler{manager: s.manager, jwtSecret: secret}
+	now := time.Now()
+	sign := func(cl claims, key []byte) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, cl).SignedString(key)
+		if err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		return token
+	}
+	standard := func(subject string, expiresAt time.Time) jwt.StandardClaims {
+		cl := jwt.StandardClaims{Subject: subject, IssuedAt: now.Unix()}
+		if !expiresAt.IsZero() {
+			cl.ExpiresAt = expiresAt.Unix()
+		}
+		return cl
+	}
+
+	var testCases = []struct {
+		name    string
+		token   string
+		want    model.Principal
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: sign(claims{Role: model.RoleEditor, Groups: []string{"sales"}, Tenant: "acme", StandardClaims: standard("alice", now.Add(time.Hour))}, secret),
+			want:  model.Principal{Name: "alice", Role: model.RoleEditor, Groups: []string{"sales"}, Tenant: "acme"},
+		},
+		{
+			name:    "expired token",
+			token:   sign(claims{Role: model.RoleEditor, StandardClaims: standard("alice", now.Add(-time.Hour))}, secret),
+			wantErr: true,
+		},
+		{
+			name:    "token without expiration",
+			token:   sign(claims{Role: model.RoleEditor, StandardClaims: standard("alice", time.Time{})}, secret),
+			wantErr: true,
+		},
+		{
+			name:    "other secret",
+			token:   sign(claims{Role: model.RoleEditor, StandardClaims: standard("alice", now.Add(time.Hour))}, []byte("other secret")),
+			wantErr: true,
+		},
+		{
+			name:    "token without subject",
+			token:   sign(claims{Role: model.RoleEditor, StandardClaims: standard("", now.Add(time.Hour))}, secret),
+			wantErr: true,
+		},
+		{
+			name:    "invalid role",
+			token:   sign(claims{Role: "root", StandardClaims: standard("alice", now.Add(time.Hour))}, secret),
+			wantErr: true,
+		},
+		{
+			name:    "not a token",
+			token:   "not a token",
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := s.e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+			got, err := h.parseJWT(c, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected principal mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
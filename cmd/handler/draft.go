@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type draftResponse struct {
+	ID       string            `json:"id"`
+	Author   string            `json:"author"`
+	Value    model.Value       `json:"value"`
+	Status   model.DraftStatus `json:"status"`
+	Reviewer string            `json:"reviewer,omitempty"`
+	Comment  string            `json:"comment,omitempty"`
+}
+
+func (r *draftResponse) Marshal(d model.Draft) {
+	r.ID = d.ID.String()
+	r.Author = d.Author
+	r.Value = d.Value
+	r.Status = d.Status
+	r.Reviewer = d.Reviewer
+	r.Comment = d.Comment
+}
+
+func (h *handler) drafts(c echo.Context) error {
+	key := c.Param("key")
+	q, err := h.manager.Get(h.context(c), model.Key(key))
+	if to, ok := movedTo(err); ok {
+		return redirect(c, questionsPath(c)+url.PathEscape(string(to))+"/drafts")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	var l = make([]draftResponse, len(q.Drafts))
+	for i, d := range q.Drafts {
+		l[i].Marshal(d)
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+func (h *handler) proposeDraft(c echo.Context) error {
+	key := c.Param("key")
+	value := c.FormValue("value")
+	d, err := h.manager.ProposeDraft(h.context(c), model.Key(key), model.Value(value))
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	var rsp draftResponse
+	rsp.Marshal(d)
+	return c.JSON(http.StatusCreated, rsp)
+}
+
+func (h *handler) approveDraft(c echo.Context) error {
+	return h.changeDraft(c, func(key model.Key, id utils.ID) error {
+		return h.manager.ApproveDraft(h.context(c), key, id, c.FormValue("comment"))
+	})
+}
+
+func (h *handler) rejectDraft(c echo.Context) error {
+	return h.changeDraft(c, func(key model.Key, id utils.ID) error {
+		return h.manager.RejectDraft(h.context(c), key, id, c.FormValue("comment"))
+	})
+}
+
+func (h *handler) publishDraft(c echo.Context) error {
+	return h.changeDraft(c, func(key model.Key, id utils.ID) error {
+		return h.manager.PublishDraft(h.context(c), key, id)
+	})
+}
+
+// changeDraft applies change to the draft identified by the id parameter of
+// the question at the key parameter.
+func (h *handler) changeDraft(c echo.Context, change func(key model.Key, id utils.ID) error) error {
+	id, err := utils.ParseID(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+	err = change(model.Key(c.Param("key")), id)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
@@ -1,14 +1,78 @@
 package handler
 
 import (
+	"context"
+	"time"
+
 	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"golang.org/x/text/language"
 )
 
 type QuestionManager interface {
-	New(key model.Key, value model.Value) (*model.Question, error)
-	Update(key model.Key, value model.Value) error
-	Delete(key model.Key) error
-	Get(key model.Key) (model.Question, error)
-	List() ([]model.Question, error)
-	History(model.Key) ([]model.Event, error)
+	New(ctx context.Context, key model.Key, value model.Value) (*model.Question, error)
+	NewTemplate(ctx context.Context, key model.Key, value model.Value) (*model.Question, error)
+	SetTemplate(ctx context.Context, key model.Key, on bool) error
+	ProposeDraft(ctx context.Context, key model.Key, value model.Value) (model.Draft, error)
+	ApproveDraft(ctx context.Context, key model.Key, id utils.ID, comment string) error
+	RejectDraft(ctx context.Context, key model.Key, id utils.ID, comment string) error
+	PublishDraft(ctx context.Context, key model.Key, id utils.ID) error
+	SetValidity(ctx context.Context, key model.Key, from, until time.Time) error
+	ScheduleValue(ctx context.Context, key model.Key, value model.Value, at time.Time) error
+	CancelSchedule(ctx context.Context, key model.Key) error
+	CreateTenant(ctx context.Context, name string) error
+	Tenants(ctx context.Context) ([]string, error)
+	DeleteTenant(ctx context.Context, name string) error
+	CreateAPIKey(ctx context.Context, name string, role model.Role, tenant string) (model.APIKey, string, error)
+	Authenticate(ctx context.Context, key string) (model.Principal, error)
+	APIKeys(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, name string) error
+	Grant(ctx context.Context, key model.Key, subject string, permission model.Permission) error
+	Revoke(ctx context.Context, key model.Key, subject string) error
+	Explain(ctx context.Context, key model.Key, p model.Principal) ([]model.Access, error)
+	CreateUser(ctx context.Context, name, password string, role model.Role, tenant string) (model.User, error)
+	User(ctx context.Context, name string) (model.User, error)
+	Users(ctx context.Context) ([]model.User, error)
+	SetUserRole(ctx context.Context, name string, role model.Role) error
+	DeleteUser(ctx context.Context, name string) error
+	AddMember(ctx context.Context, group, name string) error
+	RemoveMember(ctx context.Context, group, name string) error
+	Groups(ctx context.Context) ([]model.Group, error)
+	Login(ctx context.Context, name, password string) (model.User, error)
+	CreateResetToken(ctx context.Context, name string) (string, error)
+	ResetPassword(ctx context.Context, token, password string) error
+	AddAlias(ctx context.Context, key, alias model.Key) error
+	RemoveAlias(ctx context.Context, key, alias model.Key) error
+	Dependents(ctx context.Context, key model.Key) ([]model.Key, error)
+	Transclude(ctx context.Context, value model.Value, template bool, vars map[string]string, prefs []language.Tag, def language.Tag) (model.Value, error)
+	Update(ctx context.Context, key model.Key, value model.Value) error
+	UpdateIfVersion(ctx context.Context, key model.Key, value model.Value, expected ...int) error
+	Delete(ctx context.Context, key model.Key) error
+	DeleteIfVersion(ctx context.Context, key model.Key, expected ...int) error
+	Restore(ctx context.Context, key model.Key) error
+	Rename(ctx context.Context, old, key model.Key) error
+	Get(ctx context.Context, key model.Key) (model.Question, error)
+	GetByID(ctx context.Context, id utils.ID) (model.Question, error)
+	GetAt(ctx context.Context, key model.Key, version int) (model.Question, error)
+	GetAsOf(ctx context.Context, key model.Key, t time.Time) (model.Question, error)
+	List(ctx context.Context) ([]model.Question, error)
+	ListPage(ctx context.Context, opts model.ListOptions) ([]model.Question, string, error)
+	History(ctx context.Context, key model.Key) ([]model.Envelope, error)
+	Search(ctx context.Context, query string, limit int) ([]model.SearchResult, error)
+	Ask(ctx context.Context, query string, n int) ([]model.Match, error)
+	Misses(ctx context.Context, limit int) ([]model.Miss, error)
+	DismissMiss(ctx context.Context, key model.Key) error
+	AddAnswer(ctx context.Context, key model.Key, value model.Value) (model.Answer, error)
+	EditAnswer(ctx context.Context, key model.Key, id utils.ID, value model.Value) error
+	RemoveAnswer(ctx context.Context, key model.Key, id utils.ID) error
+	Vote(ctx context.Context, key model.Key, id utils.ID, vote int) error
+	AcceptAnswer(ctx context.Context, key model.Key, id utils.ID) error
+	Tag(ctx context.Context, key model.Key, tag string) error
+	Untag(ctx context.Context, key model.Key, tag string) error
+	Tags(ctx context.Context) ([]model.TagCount, error)
+	Translate(ctx context.Context, key model.Key, locale string, value model.Value) error
+	RemoveTranslation(ctx context.Context, key model.Key, locale string) error
+	MissingTranslations(ctx context.Context, locales []string) ([]model.MissingTranslation, error)
+	OutdatedTranslations(ctx context.Context, locale string) ([]model.OutdatedTranslation, error)
 }
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+// WithLocales sets the locales the questions are expected to be translated
+// to. The first one is the locale of the default value of the questions.
+func WithLocales(locales ...language.Tag) Option {
+	return func(h *handler) {
+		h.locales = locales
+	}
+}
+
+// localize sets the value of rsp to the translation of q preferred by the
+// Accept-Language header of the request, and returns the locales preferred.
+func (h *handler) localize(c echo.Context, rsp *response, q model.Question) []language.Tag {
+	c.Response().Header().Add(echo.HeaderVary, headerAcceptLanguage)
+	prefs, _, err := language.ParseAcceptLanguage(c.Request().Header.Get(headerAcceptLanguage))
+	if err != nil {
+		prefs = nil
+	}
+	value, locale := q.Localize(prefs, h.defaultLocale())
+	rsp.Value = value
+	if locale != language.Und {
+		rsp.Locale = locale.String()
+		c.Response().Header().Set(headerContentLanguage, rsp.Locale)
+	}
+	return prefs
+}
+
+// defaultLocale returns the locale of the default values, language.Und when
+// no locale is configured.
+func (h *handler) defaultLocale() language.Tag {
+	if len(h.locales) == 0 {
+		return language.Und
+	}
+	return h.locales[0]
+}
+
+func (h *handler) translate(c echo.Context) error {
+	key := c.Param("key")
+	locale := c.Param("locale")
+	value := c.FormValue("value")
+	// The value in the default locale is the default value.
+	if l, err := model.ParseLocale(locale); err == nil && len(h.locales) > 0 && l == h.locales[0].String() {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s is the default locale, update the value instead", l))
+	}
+	err := h.manager.Translate(h.context(c), model.Key(key), locale, model.Value(value))
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) removeTranslation(c echo.Context) error {
+	key := c.Param("key")
+	locale := c.Param("locale")
+	err := h.manager.RemoveTranslation(h.context(c), model.Key(key), locale)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+// missingTranslations lists the questions not translated to some of the
+// locales given in the query, or to the configured ones.
+func (h *handler) missingTranslations(c echo.Context) error {
+	locales := c.QueryParams()["locale"]
+	if len(locales) == 0 && len(h.locales) > 1 {
+		for _, tag := range h.locales[1:] {
+			locales = append(locales, tag.String())
+		}
+	}
+	if len(locales) == 0 {
+		return c.JSON(http.StatusOK, []model.MissingTranslation{})
+	}
+	l, err := h.manager.MissingTranslations(h.context(c), locales)
+	if err != nil {
+		return badRequest(err)
+	}
+	if l == nil {
+		l = []model.MissingTranslation{}
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+// outdatedTranslations lists the translations to review after a change of
+// the default value of their question, optionally in the locale of the
+// query.
+func (h *handler) outdatedTranslations(c echo.Context) error {
+	l, err := h.manager.OutdatedTranslations(h.context(c), c.QueryParam("locale"))
+	if err != nil {
+		return badRequest(err)
+	}
+	if l == nil {
+		l = []model.OutdatedTranslation{}
+	}
+	return c.JSON(http.StatusOK, l)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+func TestGetLocalized(t *testing.T) {
+	s := newTestServer(t, WithLocales(language.English, language.Spanish))
+	ctx := context.Background()
+	if _, err := s.manager.New(ctx, "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.manager.Translate(ctx, "refunds", "es", "Los reembolsos tardan 5 días"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name           string
+		acceptLanguage string
+		want           model.Value
+		wantLocale     string
+	}{
+		{
+			name:           "translation",
+			acceptLanguage: "es-MX, en;q=0.5",
+			want:           "Los reembolsos tardan 5 días",
+			wantLocale:     "es",
+		},
+		{
+			name:           "default locale first",
+			acceptLanguage: "en, es;q=0.5",
+			want:           "Refunds take 5 days",
+			wantLocale:     "en",
+		},
+		{
+			name:           "locale not translated",
+			acceptLanguage: "fr",
+			want:           "Refunds take 5 days",
+			wantLocale:     "en",
+		},
+		{
+			name:       "no preference",
+			want:       "Refunds take 5 days",
+			wantLocale: "en",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{headerAcceptLanguage: {tt.acceptLanguage}}
+			rec := s.do(http.MethodGet, "/questions/refunds", model.RoleReader, nil, header)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			var rsp response
+			if err := json.NewDecoder(rec.Body).Decode(&rsp); err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if rsp.Value != tt.want || rsp.Locale != tt.wantLocale {
+				t.Errorf("got = %q in %s, want %q in %s", rsp.Value, rsp.Locale, tt.want, tt.wantLocale)
+			}
+			if got := rec.Header().Get(headerContentLanguage); got != tt.wantLocale {
+				t.Errorf("got %s %q, want %q", headerContentLanguage, got, tt.wantLocale)
+			}
+			if got := rec.Header().Get(echo.HeaderVary); got != headerAcceptLanguage {
+				t.Errorf("got %s %q, want %q", echo.HeaderVary, got, headerAcceptLanguage)
+			}
+		})
+	}
+}
+
+func TestTranslateDefaultLocale(t *testing.T) {
+	s := newTestServer(t, WithLocales(language.AmericanEnglish, language.Spanish))
+	if _, err := s.manager.New(context.Background(), "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		locale   string
+		wantCode int
+	}{
+		{
+			locale:   "en-us",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			locale:   "es",
+			wantCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.locale, func(t *testing.T) {
+			form := url.Values{"value": {"Refunds take a week"}}
+			rec := s.do(http.MethodPut, "/questions/refunds/translations/"+tt.locale, model.RoleAdmin, form, nil)
+			if rec.Code != tt.wantCode {
+				t.Errorf("got = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+	if got := s.value(t, "refunds"); got != "Refunds take 5 days" {
+		t.Errorf("got = %q, want %q", got, "Refunds take 5 days")
+	}
+}
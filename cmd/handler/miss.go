@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) misses(c echo.Context) error {
+	limit := defaultPageSize
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit, expected a number between 1 and %d", maxPageSize))
+		}
+		limit = n
+	}
+	misses, err := h.manager.Misses(h.context(c), limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if misses == nil {
+		misses = []model.Miss{}
+	}
+	return c.JSON(http.StatusOK, misses)
+}
+
+// answerMiss creates the question missing at key, which removes the miss.
+func (h *handler) answerMiss(c echo.Context) error {
+	key := c.Param("key")
+	value := c.FormValue("value")
+	q, err := h.manager.New(h.context(c), model.Key(key), model.Value(value))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	var rsp response
+	rsp.Marshal(*q)
+	return c.JSON(http.StatusCreated, rsp)
+}
+
+func (h *handler) dismissMiss(c echo.Context) error {
+	key := c.Param("key")
+	if err := h.manager.DismissMiss(h.context(c), model.Key(key)); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
@@ -1,110 +1,479 @@
 package handler
 
 import (
-	"answer.io/pkg/model"
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+	headerLink    = "Link"
+
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
 )
 
 type response struct {
+	ID    string      `json:"id"`
 	Key   model.Key   `json:"key"`
 	Value model.Value `json:"value"`
+	Tags  []string    `json:"tags,omitempty"`
+	// Locale is the locale of Value, when it is known.
+	Locale   string      `json:"locale,omitempty"`
+	Template bool        `json:"template,omitempty"`
+	Aliases  []model.Key `json:"aliases,omitempty"`
+	// ValidFrom and ValidUntil are nil when unbounded.
+	ValidFrom  *time.Time    `json:"valid_from,omitempty"`
+	ValidUntil *time.Time    `json:"valid_until,omitempty"`
+	ACL        []model.Grant `json:"acl,omitempty"`
 }
 
 func (r *response) Marshal(q model.Question) {
+	r.ID = q.Id.String()
 	r.Key = q.Key
 	r.Value = q.Value
+	r.Tags = q.Tags
+	r.Template = q.Template
+	r.Aliases = q.Aliases
+	r.ACL = q.ACL
+	r.ValidFrom, r.ValidUntil = nil, nil
+	if !q.ValidFrom.IsZero() {
+		r.ValidFrom = &q.ValidFrom
+	}
+	if !q.ValidUntil.IsZero() {
+		r.ValidUntil = &q.ValidUntil
+	}
+}
+
+type historyEntry struct {
+	model.Envelope
+	ID    string `json:"id"`
+	Event string `json:"event"`
+	Data  model.Data
 }
 
 type handler struct {
 	manager QuestionManager
+	locales []language.Tag
+
+	jwtSecret     []byte
+	jwtPublicKey  *rsa.PublicKey
+	anonymousRole model.Role
+	tokenTTL      time.Duration
 }
 
-func NewQuestionHandler(e *echo.Echo, manager QuestionManager) {
-	h := &handler{manager: manager}
-	g := e.Group("questions")
-	g.POST("/", h.post)
-	g.POST("", h.post)
-	g.GET("/", h.list)
-	g.GET("", h.list)
-	g.GET("/:key/history", h.history)
-	g.GET("/:key", h.get)
-	g.PUT("/:key", h.put)
-	g.DELETE("/:key", h.delete)
+// Option configures the handler.
+type Option func(*handler)
+
+func NewQuestionHandler(e *echo.Echo, manager QuestionManager, opts ...Option) {
+	h := &handler{manager: manager, tokenTTL: defaultTokenTTL}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.routes(e.Group(""))
+	h.routes(e.Group("/t/:tenant"))
+
+	admin := h.requireAllTenants(model.RoleAdmin)
+	a := e.Group("/admin")
+	a.GET("/tenants", h.tenants, admin)
+	a.POST("/tenants", h.createTenant, admin)
+	a.DELETE("/tenants/:name", h.deleteTenant, admin)
+	a.GET("/api-keys", h.apiKeys, admin)
+	a.POST("/api-keys", h.createAPIKey, admin)
+	a.DELETE("/api-keys/:name", h.revokeAPIKey, admin)
+	a.GET("/users", h.users, admin)
+	a.POST("/users", h.createUser, admin)
+	a.GET("/users/:name", h.user, admin)
+	a.PUT("/users/:name/role", h.setUserRole, admin)
+	a.DELETE("/users/:name", h.deleteUser, admin)
+	a.POST("/users/:name/reset-token", h.createResetToken, admin)
+	a.GET("/groups", h.groups, admin)
+	a.PUT("/groups/:group/members/:name", h.addMember, admin)
+	a.DELETE("/groups/:group/members/:name", h.removeMember, admin)
+
+	e.POST("/login", h.login)
+	e.POST("/password/reset", h.resetPassword)
+}
+
+// routes registers in r the routes serving the questions of a tenant.
+func (h *handler) routes(r *echo.Group) {
+	read := h.require(model.RoleReader)
+	edit := h.require(model.RoleEditor)
+
+	g := r.Group("/questions")
+	g.POST("/", h.post, edit)
+	g.POST("", h.post, edit)
+	g.GET("/", h.list, read)
+	g.GET("", h.list, read)
+	g.GET("/search", h.search, read)
+	g.GET("/by-id/:id", h.byID(h.get), read)
+	g.PUT("/by-id/:id", h.byID(h.put), edit)
+	g.DELETE("/by-id/:id", h.byID(h.delete), edit)
+	g.GET("/by-id/:id/history", h.byID(h.history), read)
+	g.GET("/:key/history", h.history, read)
+	g.GET("/:key", h.get, read)
+	g.PUT("/:key", h.put, edit)
+	g.DELETE("/:key", h.delete, edit)
+	g.POST("/:key/restore", h.restore, edit)
+	g.POST("/:key/rename", h.rename, edit)
+	g.GET("/:key/dependents", h.dependents, read)
+	g.GET("/:key/answers", h.answers, read)
+	g.POST("/:key/answers", h.postAnswer, edit)
+	g.PUT("/:key/answers/:id", h.putAnswer, edit)
+	g.DELETE("/:key/answers/:id", h.deleteAnswer, edit)
+	g.POST("/:key/answers/:id/votes", h.vote, read)
+	g.POST("/:key/answers/:id/accept", h.acceptAnswer, edit)
+	g.GET("/:key/drafts", h.drafts, read)
+	g.POST("/:key/drafts", h.proposeDraft, edit)
+	g.POST("/:key/drafts/:id/approve", h.approveDraft, edit)
+	g.POST("/:key/drafts/:id/reject", h.rejectDraft, edit)
+	g.POST("/:key/drafts/:id/publish", h.publishDraft, edit)
+	g.PUT("/:key/validity", h.setValidity, edit)
+	g.PUT("/:key/schedule", h.scheduleValue, edit)
+	g.DELETE("/:key/schedule", h.cancelSchedule, edit)
+	g.POST("/:key/aliases", h.addAlias, edit)
+	g.DELETE("/:key/aliases/:alias", h.removeAlias, edit)
+	g.POST("/:key/tags", h.tag, edit)
+	g.DELETE("/:key/tags/:tag", h.untag, edit)
+	g.PUT("/:key/template", h.setTemplate, edit)
+	g.PUT("/:key/translations/:locale", h.translate, edit)
+	g.DELETE("/:key/translations/:locale", h.removeTranslation, edit)
+	g.PUT("/:key/acl/:subject", h.grant, edit)
+	g.DELETE("/:key/acl/:subject", h.revoke, edit)
+	r.GET("/tags", h.tags, read)
+	r.GET("/translations/outdated", h.outdatedTranslations, edit)
+	r.POST("/ask", h.ask, read)
+
+	a := r.Group("/admin")
+	a.GET("/misses", h.misses, edit)
+	a.POST("/misses/:key/question", h.answerMiss, edit)
+	a.DELETE("/misses/:key", h.dismissMiss, edit)
+	a.GET("/translations/missing", h.missingTranslations, edit)
+	a.GET("/questions/:key/access", h.explain, h.require(model.RoleAdmin))
+}
+
+// context returns the context of the request with the metadata recorded on
+// the events raised while serving it.
+func (h *handler) context(c echo.Context) context.Context {
+	ctx := c.Request().Context()
+	if p, ok := principal(c); ok {
+		ctx = model.NewContextWithPrincipal(ctx, p)
+		if p.Name != "" {
+			ctx = model.NewContextWithActor(ctx, p.Name)
+		}
+	}
+	id := c.Request().Header.Get(echo.HeaderXRequestID)
+	if id == "" {
+		id = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+	if id != "" {
+		ctx = model.NewContextWithCausationID(ctx, id)
+	}
+	if tenant := c.Param("tenant"); tenant != "" {
+		ctx = model.NewContextWithTenant(ctx, tenant)
+	}
+	source := c.Request().Referer()
+	if source == "" {
+		source = c.Request().UserAgent()
+	}
+	if source != "" {
+		ctx = model.NewContextWithSource(ctx, source)
+	}
+	return ctx
+}
+
+// byID serves the question identified by the id parameter with next, as if
+// it was requested by its key.
+func (h *handler) byID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := utils.ParseID(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+		}
+		q, err := h.manager.GetByID(h.context(c), id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		// The other parameters, like the tenant, are kept. The names are
+		// copied, they are shared by the requests of the route.
+		names := append([]string(nil), c.ParamNames()...)
+		values := append([]string(nil), c.ParamValues()...)
+		for i, name := range names {
+			if name == "id" {
+				names[i], values[i] = "key", string(q.Key)
+			}
+		}
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+		return next(c)
+	}
 }
 
 func (h *handler) post(c echo.Context) error {
 	key := c.FormValue("key")
 	value := c.FormValue("value")
-	q, err := h.manager.New(model.Key(key), model.Value(value))
+	var template bool
+	if v := c.FormValue("template"); v != "" {
+		var err error
+		if template, err = strconv.ParseBool(v); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid template")
+		}
+	}
+	var (
+		q   *model.Question
+		err error
+	)
+	if template {
+		q, err = h.manager.NewTemplate(h.context(c), model.Key(key), model.Value(value))
+	} else {
+		q, err = h.manager.New(h.context(c), model.Key(key), model.Value(value))
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return badRequest(err)
 	}
-	return c.JSON(http.StatusNoContent, response{
-		Key:   q.Key,
-		Value: q.Value,
-	})
+	var rsp response
+	rsp.Marshal(*q)
+	return c.JSON(http.StatusCreated, rsp)
 }
 
 func (h *handler) put(c echo.Context) error {
 	key := c.Param("key")
 	newValue := c.FormValue("value")
-	if err := h.manager.Update(model.Key(key), model.Value(newValue)); err != nil {
-		return echo.ErrBadRequest
+	versions, ok, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+	if ok {
+		err = h.manager.UpdateIfVersion(h.context(c), model.Key(key), model.Value(newValue), versions...)
+	} else {
+		err = h.manager.Update(h.context(c), model.Key(key), model.Value(newValue))
+	}
+	if errors.Is(err, derrors.Conflict) {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
 	}
 	return c.String(http.StatusNoContent, "")
 }
 
 func (h *handler) delete(c echo.Context) error {
 	key := c.Param("key")
-	if err := h.manager.Delete(model.Key(key)); err != nil {
+	versions, ok, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+	if ok {
+		err = h.manager.DeleteIfVersion(h.context(c), model.Key(key), versions...)
+	} else {
+		err = h.manager.Delete(h.context(c), model.Key(key))
+	}
+	if errors.Is(err, derrors.Conflict) {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if errors.Is(err, derrors.Forbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
 		return echo.ErrNotFound
 	}
 	return c.String(http.StatusNoContent, "")
 }
 
+func (h *handler) restore(c echo.Context) error {
+	key := c.Param("key")
+	if err := h.manager.Restore(h.context(c), model.Key(key)); err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) rename(c echo.Context) error {
+	key := c.Param("key")
+	newKey := c.FormValue("key")
+	if err := h.manager.Rename(h.context(c), model.Key(key), model.Key(newKey)); err != nil {
+		if _, ok := movedTo(err); ok {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+// badRequest returns the HTTP error of err, a bad request unless the
+// principal of the request isn't allowed the change.
+func badRequest(err error) error {
+	if errors.Is(err, derrors.Forbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
+
+// movedTo returns the key a question was renamed to when err tells that it
+// moved.
+func movedTo(err error) (model.Key, bool) {
+	var moved *model.MovedError
+	if errors.As(err, &moved) {
+		return moved.Key, true
+	}
+	return "", false
+}
+
+// questionsPath returns the path of the questions of the tenant of the
+// request.
+func questionsPath(c echo.Context) string {
+	if tenant := c.Param("tenant"); tenant != "" {
+		return "/t/" + url.PathEscape(tenant) + "/questions/"
+	}
+	return "/questions/"
+}
+
+// redirect answers with a permanent redirect to path with the query of the
+// request.
+func redirect(c echo.Context, path string) error {
+	u := url.URL{Path: path, RawQuery: c.QueryString()}
+	return c.Redirect(http.StatusMovedPermanently, u.String())
+}
+
+// etag returns the entity tag of the version of a question. It is weak: the
+// representations of a version differ by locale, variables and the values
+// included.
+func etag(version int) string {
+	return "W/" + strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatch returns the versions listed by the If-Match header of the
+// request. ok is false when the header is missing or matches any version.
+func ifMatch(c echo.Context) (versions []int, ok bool, err error) {
+	v := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if v == "" || v == "*" {
+		return nil, false, nil
+	}
+	for _, tag := range strings.Split(v, ",") {
+		s, err := strconv.Unquote(strings.TrimPrefix(strings.TrimSpace(tag), "W/"))
+		if err != nil {
+			return nil, false, echo.NewHTTPError(http.StatusBadRequest, "invalid If-Match header")
+		}
+		version, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false, echo.NewHTTPError(http.StatusBadRequest, "invalid If-Match header")
+		}
+		versions = append(versions, version)
+	}
+	return versions, true, nil
+}
+
 func (h *handler) get(c echo.Context) error {
 	key := c.Param("key")
-	q, err := h.manager.Get(model.Key(key))
+	var (
+		q   model.Question
+		err error
+	)
+	switch {
+	case c.QueryParam("version") != "":
+		version, perr := strconv.Atoi(c.QueryParam("version"))
+		if perr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid version")
+		}
+		q, err = h.manager.GetAt(h.context(c), model.Key(key), version)
+	case c.QueryParam("as_of") != "":
+		t, perr := time.Parse(time.RFC3339, c.QueryParam("as_of"))
+		if perr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid as_of, expected RFC3339")
+		}
+		q, err = h.manager.GetAsOf(h.context(c), model.Key(key), t)
+	default:
+		q, err = h.manager.Get(h.context(c), model.Key(key))
+	}
+	if to, ok := movedTo(err); ok {
+		return redirect(c, questionsPath(c)+url.PathEscape(string(to)))
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return badRequest(err)
+	}
+	if q.Deleted {
+		return echo.NewHTTPError(http.StatusBadRequest, "question deleted")
 	}
-	rsp := response{
-		Key:   q.Key,
-		Value: q.Value,
+	var rsp response
+	rsp.Marshal(q)
+	prefs := h.localize(c, &rsp, q)
+	vars := templateVars(c)
+	// The variables are rendered last, so their values can't reference
+	// other questions.
+	if rsp.Value, err = h.manager.Transclude(h.context(c), rsp.Value, q.Template, vars, prefs, h.defaultLocale()); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	if q.Template {
+		if rsp.Value, err = model.Render(rsp.Value, vars); err != nil {
+			return badRequest(err)
+		}
+		rsp.Template = false
+	}
+	c.Response().Header().Set(headerETag, etag(q.Version))
 	return c.JSON(http.StatusOK, rsp)
 }
 
 func (h *handler) history(c echo.Context) error {
 	key := c.Param("key")
-	q, err := h.manager.Get(model.Key(key))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	history, err := h.manager.History(h.context(c), model.Key(key))
+	if to, ok := movedTo(err); ok {
+		return redirect(c, questionsPath(c)+url.PathEscape(string(to))+"/history")
 	}
-	var events []struct {
-		Event string `json:"event"`
-		Data  model.Data
+	if err != nil {
+		return badRequest(err)
 	}
-	for i := len(q.History) - 1; i >= 0; i-- {
-		var ev = struct {
-			Event string `json:"event"`
-			Data  model.Data
-		}{
-			Event: q.History[i].String(),
-			Data:  q.History[i].Data(),
-		}
-
-		events = append(events, ev)
+	var events []historyEntry
+	for _, e := range history {
+		events = append(events, historyEntry{
+			Envelope: e,
+			ID:       e.ID.String(),
+			Event:    e.Event.String(),
+			Data:     e.Event.Data(),
+		})
 	}
 	return c.JSON(http.StatusOK, events)
 }
 
 func (h *handler) list(c echo.Context) error {
-	list, err := h.manager.List()
+	opts, err := listOptions(c)
+	if err != nil {
+		return err
+	}
+	list, next, err := h.manager.ListPage(h.context(c), opts)
 	if err != nil {
-		return echo.ErrBadRequest
+		return badRequest(err)
+	}
+	if next != "" {
+		query := c.QueryParams()
+		query.Set("token", next)
+		u := url.URL{Path: c.Request().URL.Path, RawQuery: query.Encode()}
+		c.Response().Header().Set(headerLink, fmt.Sprintf(`<%s>; rel="next"`, u.String()))
 	}
 	var l = make([]response, len(list))
 	for i, v := range list {
@@ -112,3 +481,119 @@ func (h *handler) list(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, l)
 }
+
+type searchResult struct {
+	response
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+func (h *handler) search(c echo.Context) error {
+	query := c.QueryParam("q")
+	if strings.TrimSpace(query) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing q")
+	}
+	limit := defaultPageSize
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit, expected a number between 1 and %d", maxPageSize))
+		}
+		limit = n
+	}
+	results, err := h.manager.Search(h.context(c), query, limit)
+	if err != nil {
+		return badRequest(err)
+	}
+	var l = make([]searchResult, len(results))
+	for i, r := range results {
+		l[i].Marshal(r.Question)
+		l[i].Score = r.Score
+		l[i].Snippet = r.Snippet
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+type match struct {
+	response
+	Confidence float64 `json:"confidence"`
+}
+
+type askResponse struct {
+	Answer       *match  `json:"answer"`
+	Alternatives []match `json:"alternatives"`
+}
+
+// defaultAlternatives is the number of alternatives returned by ask.
+const defaultAlternatives = 3
+
+func (h *handler) ask(c echo.Context) error {
+	query := c.FormValue("q")
+	if strings.TrimSpace(query) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing q")
+	}
+	n := defaultAlternatives
+	if v := c.FormValue("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 0 || n > maxPageSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid n, expected a number between 0 and %d", maxPageSize))
+		}
+	}
+	matches, err := h.manager.Ask(h.context(c), query, n+1)
+	if err != nil {
+		return badRequest(err)
+	}
+	rsp := askResponse{Alternatives: []match{}}
+	for i, m := range matches {
+		var r match
+		r.Marshal(m.Question)
+		r.Confidence = m.Confidence
+		if i == 0 {
+			rsp.Answer = &r
+			continue
+		}
+		rsp.Alternatives = append(rsp.Alternatives, r)
+	}
+	return c.JSON(http.StatusOK, rsp)
+}
+
+// listOptions returns the options of a listing from the query of the
+// request.
+func listOptions(c echo.Context) (model.ListOptions, error) {
+	opts := model.ListOptions{
+		Limit:  defaultPageSize,
+		Token:  c.QueryParam("token"),
+		Prefix: model.Key(c.QueryParam("prefix")),
+		Start:  model.Key(c.QueryParam("start")),
+		End:    model.Key(c.QueryParam("end")),
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxPageSize {
+			return opts, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit, expected a number between 1 and %d", maxPageSize))
+		}
+		opts.Limit = limit
+	}
+	if v := c.QueryParam("reverse"); v != "" {
+		reverse, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, echo.NewHTTPError(http.StatusBadRequest, "invalid reverse")
+		}
+		opts.Reverse = reverse
+	}
+	for _, tag := range c.QueryParams()["tag"] {
+		tag, err := model.NormalizeTag(tag)
+		if err != nil {
+			return opts, badRequest(err)
+		}
+		opts.Tags = append(opts.Tags, tag)
+	}
+	switch c.QueryParam("match") {
+	case "", "all":
+	case "any":
+		opts.AnyTag = true
+	default:
+		return opts, echo.NewHTTPError(http.StatusBadRequest, "invalid match, expected all or any")
+	}
+	return opts, nil
+}
@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"answer.io/pkg/bolt"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// testServer serves the routes of a handler backed by a bolt service.
+type testServer struct {
+	e       *echo.Echo
+	manager QuestionManager
+	// keys are the API keys of the roles in the default tenant.
+	keys map[model.Role]string
+}
+
+func newTestServer(t *testing.T, opts ...Option) *testServer {
+	t.Helper()
+	var n int
+	utils.Generator = func() string {
+		n++
+		return fmt.Sprintf("test_id_%d", n)
+	}
+	db, err := utils.Open(filepath.Join(t.TempDir(), "answer.db"))
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := bolt.NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	srv := &testServer{e: echo.New(), manager: s, keys: map[model.Role]string{}}
+	for _, role := range []model.Role{model.RoleReader, model.RoleEditor, model.RoleAdmin} {
+		_, key, err := s.CreateAPIKey(context.Background(), string(role), role, "")
+		if err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		srv.keys[role] = key
+	}
+	NewQuestionHandler(srv.e, s, opts...)
+	return srv
+}
+
+// do serves a request with the form values form, sent as role when it isn't
+// empty.
+func (s *testServer) do(method, path string, role model.Role, form url.Values, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	for name, values := range header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	if form != nil {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	}
+	if role != "" {
+		req.Header.Set(headerAPIKey, s.keys[role])
+	}
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+// value returns the value served for key.
+func (s *testServer) value(t *testing.T, key string) model.Value {
+	t.Helper()
+	rec := s.do(http.MethodGet, "/questions/"+key, model.RoleReader, nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var rsp response
+	if err := json.NewDecoder(rec.Body).Decode(&rsp); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	return rsp.Value
+}
+
+func TestPutRequiresDraft(t *testing.T) {
+	s := newTestServer(t)
+	if _, err := s.manager.New(context.Background(), "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name      string
+		role      model.Role
+		wantCode  int
+		wantValue model.Value
+	}{
+		{
+			name:      "editor",
+			role:      model.RoleEditor,
+			wantCode:  http.StatusForbidden,
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "admin",
+			role:      model.RoleAdmin,
+			wantCode:  http.StatusNoContent,
+			wantValue: "Refunds take 10 days",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"value": {"Refunds take 10 days"}}
+			rec := s.do(http.MethodPut, "/questions/refunds", tt.role, form, nil)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("got = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if got := s.value(t, "refunds"); got != tt.wantValue {
+				t.Errorf("got = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	s := newTestServer(t)
+	if _, err := s.manager.New(context.Background(), "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	rec := s.do(http.MethodGet, "/questions/refunds", model.RoleReader, nil, nil)
+	tag := rec.Header().Get(headerETag)
+	if want := `W/"0"`; tag != want {
+		t.Fatalf("got %s %q, want %q", headerETag, tag, want)
+	}
+
+	var testCases = []struct {
+		name     string
+		method   string
+		ifMatch  string
+		wantCode int
+	}{
+		{
+			name:     "invalid tag",
+			method:   http.MethodPut,
+			ifMatch:  "version",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "current version",
+			method:   http.MethodPut,
+			ifMatch:  tag,
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "outdated update",
+			method:   http.MethodPut,
+			ifMatch:  tag,
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "outdated delete",
+			method:   http.MethodDelete,
+			ifMatch:  tag,
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "invalid tag in a list",
+			method:   http.MethodPut,
+			ifMatch:  `"1", version`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "list with the current version",
+			method:   http.MethodPut,
+			ifMatch:  `"0", W/"1"`,
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "any version",
+			method:   http.MethodPut,
+			ifMatch:  "*",
+			wantCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			if tt.method == http.MethodPut {
+				form = url.Values{"value": {"Refunds take 10 days"}}
+			}
+			header := http.Header{headerIfMatch: {tt.ifMatch}}
+			rec := s.do(tt.method, "/questions/refunds", model.RoleAdmin, form, header)
+			if rec.Code != tt.wantCode {
+				t.Errorf("got = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+}
+
+func TestGetRenamed(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	if err := s.manager.CreateTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	_, ops, err := s.manager.CreateAPIKey(ctx, "ops", model.RoleReader, model.AllTenants)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, ctx := range []context.Context{ctx, model.NewContextWithTenant(ctx, "acme")} {
+		if _, err := s.manager.New(ctx, "refunds", "Refunds take 5 days"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		if err := s.manager.Rename(ctx, "refunds", "returns"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+
+	var testCases = []struct {
+		path         string
+		wantLocation string
+	}{
+		{
+			path:         "/questions/refunds",
+			wantLocation: "/questions/returns",
+		},
+		{
+			path:         "/questions/refunds/history",
+			wantLocation: "/questions/returns/history",
+		},
+		{
+			path:         "/t/acme/questions/refunds?var.name=Jane",
+			wantLocation: "/t/acme/questions/returns?var.name=Jane",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := s.do(http.MethodGet, tt.path, "", nil, http.Header{headerAPIKey: {ops}})
+			if rec.Code != http.StatusMovedPermanently {
+				t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusMovedPermanently, rec.Body)
+			}
+			if got := rec.Header().Get(echo.HeaderLocation); got != tt.wantLocation {
+				t.Errorf("got = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestGetTemplateVariables(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	for key, value := range map[model.Key]model.Value{
+		"greeting":  "Hello {{.name}}, see [[hours]]. [[signature]]",
+		"hours":     "9 to 5, {{not a template}}",
+		"secret":    "The code is 1234",
+		"signature": "Regards, {{.team}}",
+	} {
+		if _, err := s.manager.New(ctx, key, value); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	for _, key := range []model.Key{"greeting", "signature"} {
+		if err := s.manager.SetTemplate(ctx, key, true); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	// The variables can't reference other questions, and the questions
+	// included render as their own template flag says.
+	want := model.Value("Hello [[secret]], see 9 to 5, {{not a template}}. Regards, support")
+	if got := s.value(t, "greeting?var.team=support&var.name="+url.QueryEscape("[[secret]]")); got != want {
+		t.Errorf("got = %q, want %q", got, want)
+	}
+}
+
+func TestByIDInTenant(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	if err := s.manager.CreateTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	_, key, err := s.manager.CreateAPIKey(ctx, "acme-admin", model.RoleAdmin, "acme")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.manager.New(ctx, "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	acme := model.NewContextWithTenant(ctx, "acme")
+	q, err := s.manager.New(acme, "refunds", "Refunds take a week")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	path := "/t/acme/questions/by-id/" + q.Id.String()
+	header := http.Header{headerAPIKey: {key}}
+
+	rec := s.do(http.MethodPut, path, "", url.Values{"value": {"Refunds take 10 days"}}, header)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body)
+	}
+	// The change applies to the question of the tenant, not to the one of
+	// the default tenant with the same key.
+	got, err := s.manager.Get(acme, "refunds")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if want := model.Value("Refunds take 10 days"); got.Value != want {
+		t.Errorf("got = %q, want %q", got.Value, want)
+	}
+	if v := s.value(t, "refunds"); v != "Refunds take 5 days" {
+		t.Errorf("got = %q, want %q", v, "Refunds take 5 days")
+	}
+	rec = s.do(http.MethodGet, path, "", nil, header)
+	var rsp response
+	if err := json.NewDecoder(rec.Body).Decode(&rsp); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if want := model.Value("Refunds take 10 days"); rsp.Value != want {
+		t.Errorf("got = %q, want %q", rsp.Value, want)
+	}
+}
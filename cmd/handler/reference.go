@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// dependents lists the keys of the questions including the question at key.
+func (h *handler) dependents(c echo.Context) error {
+	key := c.Param("key")
+	l, err := h.manager.Dependents(h.context(c), model.Key(key))
+	if err != nil {
+		return badRequest(err)
+	}
+	if l == nil {
+		l = []model.Key{}
+	}
+	return c.JSON(http.StatusOK, l)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// formTime returns the RFC 3339 time of the form value name, zero when it
+// is empty.
+func formTime(c echo.Context, name string) (time.Time, error) {
+	v := c.FormValue(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return t, echo.NewHTTPError(http.StatusBadRequest, "invalid "+name+", expected RFC3339")
+	}
+	return t, nil
+}
+
+// setValidity sets the window of time a question is visible in from the
+// valid_from and valid_until form values, an empty one leaves its side open.
+func (h *handler) setValidity(c echo.Context) error {
+	key := c.Param("key")
+	from, err := formTime(c, "valid_from")
+	if err != nil {
+		return err
+	}
+	until, err := formTime(c, "valid_until")
+	if err != nil {
+		return err
+	}
+	err = h.manager.SetValidity(h.context(c), model.Key(key), from, until)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) scheduleValue(c echo.Context) error {
+	key := c.Param("key")
+	value := c.FormValue("value")
+	at, err := formTime(c, "at")
+	if err != nil {
+		return err
+	}
+	err = h.manager.ScheduleValue(h.context(c), model.Key(key), model.Value(value), at)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) cancelSchedule(c echo.Context) error {
+	key := c.Param("key")
+	err := h.manager.CancelSchedule(h.context(c), model.Key(key))
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
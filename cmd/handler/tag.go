@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) tag(c echo.Context) error {
+	key := c.Param("key")
+	tag := c.FormValue("tag")
+	err := h.manager.Tag(h.context(c), model.Key(key), tag)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) untag(c echo.Context) error {
+	key := c.Param("key")
+	tag := c.Param("tag")
+	err := h.manager.Untag(h.context(c), model.Key(key), tag)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) tags(c echo.Context) error {
+	tags, err := h.manager.Tags(h.context(c))
+	if err != nil {
+		return badRequest(err)
+	}
+	if tags == nil {
+		tags = []model.TagCount{}
+	}
+	return c.JSON(http.StatusOK, tags)
+}
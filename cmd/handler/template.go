@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"answer.io/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// templateVarPrefix is the prefix of the query parameters holding the
+// variables of a templated value.
+const templateVarPrefix = "var."
+
+// templateVars returns the variables given in the query of the request.
+func templateVars(c echo.Context) map[string]string {
+	vars := map[string]string{}
+	for name, values := range c.QueryParams() {
+		if strings.HasPrefix(name, templateVarPrefix) && len(values) > 0 {
+			vars[strings.TrimPrefix(name, templateVarPrefix)] = values[0]
+		}
+	}
+	return vars
+}
+
+// setTemplate marks the value of a question as a template or as plain text
+// with the template form value.
+func (h *handler) setTemplate(c echo.Context) error {
+	key := c.Param("key")
+	on, err := strconv.ParseBool(c.FormValue("template"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid template")
+	}
+	err = h.manager.SetTemplate(h.context(c), model.Key(key), on)
+	if _, ok := movedTo(err); ok {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"answer.io/pkg/derrors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) tenants(c echo.Context) error {
+	l, err := h.manager.Tenants(h.context(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+func (h *handler) createTenant(c echo.Context) error {
+	name := c.FormValue("name")
+	err := h.manager.CreateTenant(h.context(c), name)
+	if errors.Is(err, derrors.Conflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.String(http.StatusCreated, "")
+}
+
+// deleteTenant deletes a tenant and all its questions.
+func (h *handler) deleteTenant(c echo.Context) error {
+	name := c.Param("name")
+	err := h.manager.DeleteTenant(h.context(c), name)
+	if errors.Is(err, derrors.NotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
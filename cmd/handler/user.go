@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// tokenIssuer issues the JWTs of the users logging in.
+	tokenIssuer = "answer.io"
+
+	defaultTokenTTL = time.Hour
+)
+
+// WithTokenTTL sets the time the JWTs issued on login are valid for.
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(h *handler) {
+		h.tokenTTL = ttl
+	}
+}
+
+type tokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// login issues a JWT signed with HS256 to a user giving its password.
+func (h *handler) login(c echo.Context) error {
+	if h.jwtSecret == nil {
+		return echo.NewHTTPError(http.StatusNotImplemented, "login disabled, no JWT secret")
+	}
+	u, err := h.manager.Login(h.context(c), c.FormValue("name"), c.FormValue("password"))
+	if err != nil {
+		c.Response().Header().Set(headerWWWAuthenticate, `Bearer realm="answer.io"`)
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	now := utils.Clock()
+	rsp := tokenResponse{ExpiresAt: now.Add(h.tokenTTL).UTC().Truncate(time.Second)}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Role:   u.Role,
+		Groups: u.Groups,
+		Tenant: u.Tenant,
+		// The token is revoked by the next change of the password.
+		PasswordGeneration: u.PasswordGeneration,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   u.Name,
+			Issuer:    tokenIssuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: rsp.ExpiresAt.Unix(),
+		},
+	})
+	if rsp.Token, err = token.SignedString(h.jwtSecret); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, rsp)
+}
+
+// resetPassword sets the password of a user with a reset token.
+func (h *handler) resetPassword(c echo.Context) error {
+	err := h.manager.ResetPassword(h.context(c), c.FormValue("token"), c.FormValue("password"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) users(c echo.Context) error {
+	l, err := h.manager.Users(h.context(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+func (h *handler) user(c echo.Context) error {
+	u, err := h.manager.User(h.context(c), c.Param("name"))
+	if err != nil {
+		return userError(err)
+	}
+	return c.JSON(http.StatusOK, u)
+}
+
+func (h *handler) createUser(c echo.Context) error {
+	role, err := model.ParseRole(c.FormValue("role"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	u, err := h.manager.CreateUser(h.context(c), c.FormValue("name"), c.FormValue("password"), role, c.FormValue("tenant"))
+	if err != nil {
+		return userError(err)
+	}
+	return c.JSON(http.StatusCreated, u)
+}
+
+func (h *handler) setUserRole(c echo.Context) error {
+	role, err := model.ParseRole(c.FormValue("role"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := h.manager.SetUserRole(h.context(c), c.Param("name"), role); err != nil {
+		return userError(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) deleteUser(c echo.Context) error {
+	if err := h.manager.DeleteUser(h.context(c), c.Param("name")); err != nil {
+		return userError(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+// createResetToken returns a token the user can reset its password with
+// once.
+func (h *handler) createResetToken(c echo.Context) error {
+	token, err := h.manager.CreateResetToken(h.context(c), c.Param("name"))
+	if err != nil {
+		return userError(err)
+	}
+	return c.JSON(http.StatusCreated, struct {
+		Token string `json:"token"`
+	}{token})
+}
+
+func (h *handler) groups(c echo.Context) error {
+	l, err := h.manager.Groups(h.context(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, l)
+}
+
+func (h *handler) addMember(c echo.Context) error {
+	if err := h.manager.AddMember(h.context(c), c.Param("group"), c.Param("name")); err != nil {
+		return userError(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+func (h *handler) removeMember(c echo.Context) error {
+	if err := h.manager.RemoveMember(h.context(c), c.Param("group"), c.Param("name")); err != nil {
+		return userError(err)
+	}
+	return c.String(http.StatusNoContent, "")
+}
+
+// userError returns the HTTP error of err, returned by a change of the
+// users.
+func userError(err error) error {
+	switch {
+	case errors.Is(err, derrors.NotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, derrors.Conflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// login returns the token issued to name logging in with password.
+func (s *testServer) login(t *testing.T, name, password string) string {
+	t.Helper()
+	form := url.Values{"name": {name}, "password": {password}}
+	rec := s.do(http.MethodPost, "/login", "", form, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var rsp tokenResponse
+	if err := json.NewDecoder(rec.Body).Decode(&rsp); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	return rsp.Token
+}
+
+func TestLogin(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s := newTestServer(t, WithJWTSecret([]byte("test secret")), WithTokenTTL(time.Hour))
+	if _, err := s.manager.CreateUser(context.Background(), "alice", "password-alice", model.RoleReader, ""); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	form := url.Values{"name": {"alice"}, "password": {"password-bob"}}
+	if rec := s.do(http.MethodPost, "/login", "", form, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusUnauthorized, rec.Body)
+	}
+	header := http.Header{echo.HeaderAuthorization: {"Bearer " + s.login(t, "alice", "password-alice")}}
+
+	var testCases = []struct {
+		name     string
+		after    time.Duration
+		wantCode int
+	}{
+		{
+			name:     "valid token",
+			after:    time.Minute,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "expired token",
+			after:    time.Hour + time.Second,
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			now = time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC).Add(tt.after)
+			rec := s.do(http.MethodGet, "/questions/", "", nil, header)
+			if rec.Code != tt.wantCode {
+				t.Errorf("got = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+}
+
+func TestLoginPasswordReset(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s := newTestServer(t, WithJWTSecret([]byte("test secret")))
+	ctx := context.Background()
+	if _, err := s.manager.CreateUser(ctx, "alice", "password-alice", model.RoleReader, ""); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	old := http.Header{echo.HeaderAuthorization: {"Bearer " + s.login(t, "alice", "password-alice")}}
+
+	// The tokens issued before a reset of the password are revoked, even
+	// within the same second.
+	token, err := s.manager.CreateResetToken(ctx, "alice")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	form := url.Values{"token": {token}, "password": {"new-password"}}
+	if rec := s.do(http.MethodPost, "/password/reset", "", form, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("got = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body)
+	}
+	if rec := s.do(http.MethodGet, "/questions/", "", nil, old); rec.Code != http.StatusUnauthorized {
+		t.Errorf("got = %d, want %d: %s", rec.Code, http.StatusUnauthorized, rec.Body)
+	}
+	header := http.Header{echo.HeaderAuthorization: {"Bearer " + s.login(t, "alice", "new-password")}}
+	if rec := s.do(http.MethodGet, "/questions/", "", nil, header); rec.Code != http.StatusOK {
+		t.Errorf("got = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+}
@@ -1,30 +1,62 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"answer.io/cmd/handler"
 	"answer.io/pkg/bolt"
+	"answer.io/pkg/model"
 	"answer.io/pkg/utils"
+	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/text/language"
 )
 
-var path string
+var (
+	path             string
+	snapshotInterval uint64
+	reindex          bool
+	askThreshold     float64
+	locales          string
+	scheduleInterval time.Duration
+	jwtSecret        string
+	jwtPublicKey     string
+	anonymousRole    string
+	createAPIKey     string
+	tokenTTL         time.Duration
+	maxMisses        int
+)
 
 func main() {
 
-	flag.Parse()
-
 	flag.StringVar(&path, "path", "/tmpanswer.db", "path of the database to store the data")
+	flag.Uint64Var(&snapshotInterval, "snapshot-interval", 100, "number of events between two snapshots of a question, 0 disables them")
+	flag.BoolVar(&reindex, "reindex", false, "rebuild the search indexes from the questions and exit")
+	flag.Float64Var(&askThreshold, "ask-threshold", 0.3, "confidence between 0 and 1 below which a question doesn't answer a text asked")
+	flag.StringVar(&locales, "locales", "", "comma separated BCP 47 locales the questions are translated to, the first one is the locale of their default value")
+	flag.DurationVar(&scheduleInterval, "schedule-interval", time.Minute, "interval between two runs of the scheduler publishing scheduled values and validity windows")
+	flag.StringVar(&jwtSecret, "jwt-secret", "", "secret of the JWTs signed with HS256, a random one is used when empty and the tokens issued on login don't survive a restart")
+	flag.StringVar(&jwtPublicKey, "jwt-public-key", "", "path of the PEM encoded RSA public key of the JWTs signed with RS256, they are refused when empty")
+	flag.StringVar(&anonymousRole, "anonymous-role", string(model.RoleReader), "role of the requests without credentials in the default tenant, they are refused when empty")
+	flag.StringVar(&createAPIKey, "create-api-key", "", "create an API key for name:role[:tenant], bound to every tenant by default, print it and exit")
+	flag.IntVar(&maxMisses, "max-misses", 10000, "number of distinct missing keys recorded per tenant")
+	flag.DurationVar(&tokenTTL, "token-ttl", time.Hour, "time the tokens issued on login are valid for")
+	flag.Parse()
 
 	e := echo.New()
 	utils.Generator = func() string {
 		return uuid.NewString()
 	}
+	e.Use(middleware.RequestID())
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	db, err := utils.Open(path)
@@ -32,12 +64,114 @@ func main() {
 		log.Fatalln(err)
 		os.Exit(1)
 	}
-	manager, err := bolt.NewService(db)
+	manager, err := bolt.NewService(db,
+		bolt.WithSnapshotInterval(snapshotInterval),
+		bolt.WithAskThreshold(askThreshold),
+		bolt.WithMaxMisses(maxMisses),
+	)
 	if err != nil {
 		log.Fatalln(err)
 		os.Exit(1)
 	}
-	handler.NewQuestionHandler(e, manager)
+	if createAPIKey != "" {
+		name, role, ok := strings.Cut(createAPIKey, ":")
+		if !ok {
+			log.Fatalln("invalid -create-api-key, expected name:role[:tenant]")
+		}
+		role, tenant, ok := strings.Cut(role, ":")
+		if !ok {
+			tenant = model.AllTenants
+		}
+		_, key, err := manager.CreateAPIKey(context.Background(), name, model.Role(role), tenant)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Println(key)
+		return
+	}
+	if reindex {
+		tenants, err := manager.Tenants(context.Background())
+		if err != nil {
+			log.Fatalln(err)
+		}
+		for _, tenant := range tenants {
+			ctx := model.NewContextWithTenant(context.Background(), tenant)
+			if err := manager.Reindex(ctx); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		return
+	}
+	var tags []language.Tag
+	for _, locale := range strings.Split(locales, ",") {
+		if locale = strings.TrimSpace(locale); locale == "" {
+			continue
+		}
+		tag, err := language.Parse(locale)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		tags = append(tags, tag)
+	}
+	secret := []byte(jwtSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	opts := []handler.Option{
+		handler.WithLocales(tags...),
+		handler.WithJWTSecret(secret),
+		handler.WithTokenTTL(tokenTTL),
+	}
+	if jwtPublicKey != "" {
+		data, err := os.ReadFile(jwtPublicKey)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		opts = append(opts, handler.WithJWTPublicKey(key))
+	}
+	if anonymousRole != "" {
+		role, err := model.ParseRole(anonymousRole)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		opts = append(opts, handler.WithAnonymousRole(role))
+	}
+	handler.NewQuestionHandler(e, manager, opts...)
+	go schedule(manager, scheduleInterval)
 
 	e.Logger.Fatal(e.Start(":1323"))
 }
+
+// schedule runs the scheduler of manager on every tenant every interval.
+func schedule(manager interface {
+	Tenants(ctx context.Context) ([]string, error)
+	RunSchedule(ctx context.Context) (int, error)
+}, interval time.Duration) {
+	ctx := model.NewContextWithActor(context.Background(), "scheduler")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		tenants, err := manager.Tenants(ctx)
+		if err != nil {
+			log.Println("scheduler:", err)
+			continue
+		}
+		for _, tenant := range tenants {
+			n, err := manager.RunSchedule(model.NewContextWithTenant(ctx, tenant))
+			if err != nil {
+				log.Printf("scheduler: tenant %s: %v", tenant, err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("scheduler: tenant %s: %d questions changed", tenant, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+)
+
+// errQuestionNotReadable is returned for a question the principal isn't
+// allowed to read, telling it apart from a missing question only to the
+// service.
+var errQuestionNotReadable = fmt.Errorf("question %w", derrors.NotFound)
+
+// authorize returns an error when the principal of ctx isn't allowed
+// permission on q. The contexts without principal, like the ones of the
+// scheduler, are allowed everything. The questions the principal can't read
+// look missing.
+func authorize(ctx context.Context, q model.Question, permission model.Permission) error {
+	p, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	a := q.Access(p, permission)
+	switch {
+	case a.Allowed:
+		return nil
+	case permission == model.PermissionRead || !q.Can(p, model.PermissionRead):
+		return errQuestionNotReadable
+	default:
+		return fmt.Errorf("%s: %w", a.Reason, derrors.Forbidden)
+	}
+}
+
+// authorizeKey is like authorize for the question stored at key, deleted or
+// not.
+func authorizeKey(ctx context.Context, tx *tenantTx, key model.Key, permission model.Permission) error {
+	if _, ok := model.PrincipalFromContext(ctx); !ok {
+		return nil
+	}
+	q, err := load(tx, key)
+	if err != nil {
+		return err
+	}
+	return authorize(ctx, q, permission)
+}
+
+// readable reports whether the principal of ctx is allowed to read q.
+func readable(ctx context.Context, q model.Question) bool {
+	return authorize(ctx, q, model.PermissionRead) == nil
+}
+
+// Grant gives permission on the question stored at key to subject, a user
+// or a group. The principal of ctx must be allowed to administer the
+// question.
+func (s *service) Grant(ctx context.Context, key model.Key, subject string, permission model.Permission) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Grant")
+	return s.modifyAs(ctx, key, model.PermissionAdmin, func(q *model.Question) error {
+		return q.Grant(subject, permission)
+	})
+}
+
+// Revoke removes the grant of subject from the ACL of the question stored at
+// key.
+func (s *service) Revoke(ctx context.Context, key model.Key, subject string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Revoke")
+	return s.modifyAs(ctx, key, model.PermissionAdmin, func(q *model.Question) error {
+		return q.Revoke(subject)
+	})
+}
+
+// Explain tells whether p is allowed to read, write and administer the
+// question stored at key, and why. The principal of ctx must be allowed to
+// administer the question.
+func (s *service) Explain(ctx context.Context, key model.Key, p model.Principal) (_ []model.Access, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Explain")
+	var l []model.Access
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		q, err := get(tx, key)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, q, model.PermissionAdmin); err != nil {
+			return err
+		}
+		for _, permission := range []model.Permission{model.PermissionRead, model.PermissionWrite, model.PermissionAdmin} {
+			l = append(l, q.Access(p, permission))
+		}
+		return nil
+	})
+	return l, err
+}
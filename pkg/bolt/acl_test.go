@@ -0,0 +1,172 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceACL(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, q := range []struct {
+		key   model.Key
+		value model.Value
+	}{
+		{"pricing", "Enterprise pricing is negotiated"},
+		{"refunds", "Refunds take 5 days"},
+	} {
+		if _, err := s.New(ctx, q.key, q.value); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	editor := model.NewContextWithPrincipal(ctx, model.Principal{Name: "alice", Role: model.RoleEditor})
+	for _, g := range []model.Grant{
+		{Subject: "user:alice", Permission: model.PermissionAdmin},
+		{Subject: "group:sales", Permission: model.PermissionRead},
+		{Subject: "user:bob", Permission: model.PermissionWrite},
+	} {
+		if err := s.Grant(editor, "pricing", g.Subject, g.Permission); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	bob := model.NewContextWithPrincipal(ctx, model.Principal{Name: "bob", Role: model.RoleEditor})
+	if err := s.Grant(bob, "pricing", "user:bob", model.PermissionAdmin); !errors.Is(err, derrors.Forbidden) {
+		t.Fatalf("got = %v, want forbidden", err)
+	}
+
+	var testCases = []struct {
+		name     string
+		p        model.Principal
+		want     []model.Key
+		canWrite bool
+	}{
+		{
+			name: "without grant",
+			p:    model.Principal{Name: "carol", Role: model.RoleEditor},
+			want: []model.Key{"refunds"},
+		},
+		{
+			name: "group grant",
+			p:    model.Principal{Name: "carol", Role: model.RoleReader, Groups: []string{"sales"}},
+			want: []model.Key{"pricing", "refunds"},
+		},
+		{
+			name:     "user grant",
+			p:        model.Principal{Name: "bob", Role: model.RoleEditor},
+			want:     []model.Key{"pricing", "refunds"},
+			canWrite: true,
+		},
+		{
+			name:     "administrator",
+			p:        model.Principal{Name: "root", Role: model.RoleAdmin},
+			want:     []model.Key{"pricing", "refunds"},
+			canWrite: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := model.NewContextWithPrincipal(ctx, tt.p)
+			l, err := s.List(ctx)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			var got []model.Key
+			for _, q := range l {
+				got = append(got, q.Key)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected list mismatch (-want +got):\n%s", diff)
+			}
+
+			results, err := s.Search(ctx, "pricing", 10)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			readable := len(tt.want) == 2
+			checkAsserts(t, len(results) == 1, readable)
+
+			_, err = s.Get(ctx, "pricing")
+			checkAsserts(t, err == nil, readable)
+			_, err = s.History(ctx, "pricing")
+			checkAsserts(t, err == nil, readable)
+			_, err = s.GetAt(ctx, "pricing", 0)
+			checkAsserts(t, err == nil, readable)
+
+			err = s.Tag(ctx, "pricing", tt.p.Name)
+			checkAsserts(t, err == nil, tt.canWrite)
+			if readable && !tt.canWrite && !errors.Is(err, derrors.Forbidden) {
+				t.Errorf("got = %v, want forbidden", err)
+			}
+		})
+	}
+
+	// The scheduler and the other contexts without principal see everything.
+	if _, err := s.Get(ctx, "pricing"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	access, err := s.Explain(ctx, "pricing", model.Principal{Name: "carol", Role: model.RoleReader, Groups: []string{"sales"}})
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	var allowed []bool
+	for _, a := range access {
+		allowed = append(allowed, a.Allowed)
+	}
+	if diff := cmp.Diff([]bool{true, false, false}, allowed); diff != "" {
+		t.Errorf("unexpected access mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := s.Revoke(ctx, "pricing", "group:sales"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	q, err := s.Get(ctx, "pricing")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	want := []model.Grant{
+		{Subject: "user:alice", Permission: model.PermissionAdmin},
+		{Subject: "user:bob", Permission: model.PermissionWrite},
+	}
+	if diff := cmp.Diff(want, q.ACL); diff != "" {
+		t.Errorf("unexpected ACL mismatch (-want +got):\n%s", diff)
+	}
+
+	// The ACL outlives a delete, and only the principals allowed to write
+	// the deleted question create it again.
+	if err := s.Delete(bob, "pricing"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	carol := model.NewContextWithPrincipal(ctx, model.Principal{Name: "carol", Role: model.RoleEditor})
+	if _, err := s.New(carol, "pricing", "Enterprise pricing is public"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if _, err := s.New(ctx, "pricing", "Enterprise pricing is public"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	q, err = s.Get(ctx, "pricing")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, q.ACL); diff != "" {
+		t.Errorf("unexpected ACL mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := s.Get(carol, "pricing"); err == nil {
+		t.Errorf("got = nil, want error")
+	}
+}
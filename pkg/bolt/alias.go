@@ -0,0 +1,82 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+)
+
+// AddAlias adds alias as another key of the question stored at key.
+func (s *service) AddAlias(ctx context.Context, key, alias model.Key) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.AddAlias")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.AddAlias(alias)
+	})
+}
+
+func (s *service) RemoveAlias(ctx context.Context, key, alias model.Key) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RemoveAlias")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.RemoveAlias(alias)
+	})
+}
+
+// putAliases points the aliases of q to its key, or removes them when q is
+// deleted. It fails when an alias is the key of another question or an
+// alias of it.
+func putAliases(tx *tenantTx, q *model.Question, events []model.Event) error {
+	aBucket := tx.Bucket(aliasBucket)
+	if aBucket == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	for _, ev := range events {
+		if e, ok := ev.(model.AliasRemoved); ok {
+			if err := aBucket.Delete([]byte(e.Alias)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, alias := range q.Aliases {
+		if q.Deleted {
+			if err := aBucket.Delete([]byte(alias)); err != nil {
+				return err
+			}
+			continue
+		}
+		if live(tx, alias) {
+			return fmt.Errorf("alias %q is the key of a question: %w", alias, derrors.Conflict)
+		}
+		if to, ok := aliasOf(tx, alias); ok && to != q.Key {
+			return fmt.Errorf("alias %q is an alias of %q: %w", alias, to, derrors.Conflict)
+		}
+		if err := aBucket.Put([]byte(alias), []byte(q.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aliasOf returns the key of the question not deleted alias is an alias of.
+func aliasOf(tx *tenantTx, alias model.Key) (model.Key, bool) {
+	aBucket := tx.Bucket(aliasBucket)
+	if aBucket == nil {
+		return "", false
+	}
+	key := aBucket.Get([]byte(alias))
+	if len(key) == 0 || !live(tx, model.Key(key)) {
+		return "", false
+	}
+	return model.Key(key), true
+}
+
+// live reports whether a question not deleted is stored at key.
+func live(tx *tenantTx, key model.Key) bool {
+	qBucket := tx.Bucket(questionBucket)
+	dBucket := tx.Bucket(deletedQuestionBucket)
+	if qBucket == nil || dBucket == nil {
+		return false
+	}
+	return len(qBucket.Get([]byte(key))) > 0 && len(dBucket.Get([]byte(key))) == 0
+}
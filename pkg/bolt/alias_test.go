@@ -0,0 +1,121 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceAliases(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, key := range []model.Key{"refund-policy", "shipping"} {
+		if _, err := s.New(ctx, key, "value"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+
+	var testCases = []struct {
+		name    string
+		change  func() error
+		wantErr bool
+	}{
+		{
+			name:   "add alias",
+			change: func() error { return s.AddAlias(ctx, "refund-policy", "refunds") },
+		},
+		{
+			name:   "add alias through an alias",
+			change: func() error { return s.AddAlias(ctx, "refunds", "return_policy") },
+		},
+		{
+			name:    "alias of another question",
+			change:  func() error { return s.AddAlias(ctx, "shipping", "refunds") },
+			wantErr: true,
+		},
+		{
+			name:    "alias on the key of a question",
+			change:  func() error { return s.AddAlias(ctx, "refund-policy", "shipping") },
+			wantErr: true,
+		},
+		{
+			name: "new question at an alias",
+			change: func() error {
+				_, err := s.New(ctx, "refunds", "value")
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name:    "rename to an alias",
+			change:  func() error { return s.Rename(ctx, "shipping", "refunds") },
+			wantErr: true,
+		},
+		{
+			name:   "rename aliased question",
+			change: func() error { return s.Rename(ctx, "refund-policy", "refund-terms") },
+		},
+		{
+			name:   "remove alias",
+			change: func() error { return s.RemoveAlias(ctx, "refund-terms", "return_policy") },
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	q, err := s.Get(ctx, "refunds")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, q.Key, model.Key("refund-terms"))
+	if diff := cmp.Diff([]model.Key{"refunds"}, q.Aliases); diff != "" {
+		t.Errorf("unexpected aliases mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := s.Get(ctx, "return_policy"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+
+	// The aliases go away with their question, and come back with it.
+	if err := s.Delete(ctx, "refund-terms"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.Get(ctx, "refunds"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if err := s.Restore(ctx, "refund-terms"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.Get(ctx, "refunds"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	history, err := s.History(ctx, "refund-terms")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	var events []string
+	for _, e := range history {
+		events = append(events, e.Event.String())
+	}
+	want := []string{"restore", "delete", "unalias", "rename", "alias", "alias", "add"}
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Errorf("unexpected history mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,81 @@
+package bolt
+
+import (
+	"context"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+)
+
+// AddAnswer adds a candidate answer to the question stored at key. The actor
+// of ctx is its author.
+func (s *service) AddAnswer(ctx context.Context, key model.Key, value model.Value) (_ model.Answer, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.AddAnswer")
+	id := utils.NextID()
+	var a model.Answer
+	err = s.modify(ctx, key, func(q *model.Question) error {
+		if err := q.AddAnswer(id, model.ActorFromContext(ctx), value); err != nil {
+			return err
+		}
+		a = *q.Answer(id)
+		return nil
+	})
+	return a, err
+}
+
+func (s *service) EditAnswer(ctx context.Context, key model.Key, id utils.ID, value model.Value) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.EditAnswer")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.EditAnswer(id, value)
+	})
+}
+
+func (s *service) RemoveAnswer(ctx context.Context, key model.Key, id utils.ID) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RemoveAnswer")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.RemoveAnswer(id)
+	})
+}
+
+// Vote records the vote of the actor of ctx for an answer. Voting only
+// requires to read the question.
+func (s *service) Vote(ctx context.Context, key model.Key, id utils.ID, vote int) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Vote")
+	return s.modifyAs(ctx, key, model.PermissionRead, func(q *model.Question) error {
+		return q.Vote(id, model.ActorFromContext(ctx), vote)
+	})
+}
+
+// AcceptAnswer makes an answer the value of the question stored at key.
+func (s *service) AcceptAnswer(ctx context.Context, key model.Key, id utils.ID) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.AcceptAnswer")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.Accept(id)
+	})
+}
+
+// modify applies fn to the question stored at key and stores the events it
+// raises. The principal of ctx must be allowed to write the question.
+func (s *service) modify(ctx context.Context, key model.Key, fn func(q *model.Question) error) error {
+	return s.modifyAs(ctx, key, model.PermissionWrite, fn)
+}
+
+// modifyAs is like modify but the principal of ctx must be allowed
+// permission on the question.
+func (s *service) modifyAs(ctx context.Context, key model.Key, permission model.Permission, fn func(q *model.Question) error) error {
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		q, err := get(tx, key)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, q, permission); err != nil {
+			return err
+		}
+		n := len(q.History)
+		if err := fn(&q); err != nil {
+			return err
+		}
+		return s.put(ctx, tx, &q, q.History[n:])
+	})
+}
@@ -0,0 +1,150 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestServiceAnswers(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	var n int
+	utils.Generator = func() string {
+		n++
+		return fmt.Sprintf("test_id_%d", n)
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	ctx := context.Background()
+	john := model.NewContextWithActor(ctx, "john")
+	jane := model.NewContextWithActor(ctx, "jane")
+	if _, err := s.New(ctx, "opening-hours", "unknown"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	first, err := s.AddAnswer(john, "opening-hours", "From 9 to 5")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	second, err := s.AddAnswer(jane, "opening-hours", "From 8 to 4")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name    string
+		change  func() error
+		wantErr bool
+	}{
+		{
+			name:   "vote for",
+			change: func() error { return s.Vote(john, "opening-hours", first.ID, 1) },
+		},
+		{
+			name:   "vote twice",
+			change: func() error { return s.Vote(john, "opening-hours", first.ID, 1) },
+		},
+		{
+			name:   "vote against",
+			change: func() error { return s.Vote(jane, "opening-hours", second.ID, -1) },
+		},
+		{
+			name:    "anonymous vote",
+			change:  func() error { return s.Vote(ctx, "opening-hours", first.ID, 1) },
+			wantErr: true,
+		},
+		{
+			name:    "invalid vote",
+			change:  func() error { return s.Vote(jane, "opening-hours", first.ID, 2) },
+			wantErr: true,
+		},
+		{
+			name:   "accept answer",
+			change: func() error { return s.AcceptAnswer(ctx, "opening-hours", first.ID) },
+		},
+		{
+			name:   "edit accepted answer",
+			change: func() error { return s.EditAnswer(ctx, "opening-hours", first.ID, "From 9 to 6") },
+		},
+		{
+			name:    "remove accepted answer",
+			change:  func() error { return s.RemoveAnswer(ctx, "opening-hours", first.ID) },
+			wantErr: true,
+		},
+		{
+			name:   "remove answer",
+			change: func() error { return s.RemoveAnswer(ctx, "opening-hours", second.ID) },
+		},
+		{
+			name:    "edit unknown answer",
+			change:  func() error { return s.EditAnswer(ctx, "opening-hours", second.ID, "From 8 to 5") },
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	got, err := s.Get(ctx, "opening-hours")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	want := []model.Answer{
+		{
+			ID:     first.ID,
+			Author: "john",
+			Value:  "From 9 to 6",
+			Votes:  1,
+			Voters: map[string]int{"john": 1},
+		},
+	}
+	if diff := cmp.Diff(want, got.Answers); diff != "" {
+		t.Errorf("unexpected answers mismatch (-want +got):\n%s", diff)
+	}
+	checkAsserts(t, got.Value, model.Value("From 9 to 6"))
+	checkAsserts(t, got.Accepted, first.ID)
+	checkAsserts(t, got.Version, 7)
+}
+
+func TestServiceAnswersSnapshot(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db, WithSnapshotInterval(2))
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	ctx := model.NewContextWithActor(context.Background(), "john")
+	if _, err := s.New(ctx, "name", "John"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	a, err := s.AddAnswer(ctx, "name", "Jane")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Vote(ctx, "name", a.ID, -1); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	got, err := s.Get(ctx, "name")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	want := []model.Answer{{ID: a.ID, Author: "john", Value: "Jane", Votes: -1}}
+	if diff := cmp.Diff(want, got.Answers, cmpopts.IgnoreFields(model.Answer{}, "Voters")); diff != "" {
+		t.Errorf("unexpected answers mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,197 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// apiKeyBucket holds the API keys of every tenant by the SHA-256 hash of the
+// key, the keys themselves are never stored.
+var apiKeyBucket = []byte("api_keys")
+
+var errInvalidAPIKey = errors.New("invalid API key")
+
+// CreateAPIKey creates an API key authenticating name with role in tenant,
+// the default one when empty, or in every tenant with model.AllTenants. It
+// returns the key along with its description, it is the only time the key
+// is known.
+func (s *service) CreateAPIKey(ctx context.Context, name string, role model.Role, tenant string) (_ model.APIKey, _ string, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.CreateAPIKey")
+	if strings.TrimSpace(name) == "" {
+		return model.APIKey{}, "", errors.New("empty name")
+	}
+	if _, err := model.ParseRole(string(role)); err != nil {
+		return model.APIKey{}, "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return model.APIKey{}, "", err
+	}
+	k := model.APIKey{Name: name, Role: role, CreatedAt: utils.Clock().UTC()}
+	key := model.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		if k.Tenant, err = checkTenant(tx, tenant); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucketIfNotExists(apiKeyBucket)
+		if err != nil {
+			return err
+		}
+		exists := false
+		err = b.ForEach(func(_, v []byte) error {
+			other, err := decodeAPIKey(v)
+			exists = exists || other.Name == name
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("API key %q: %w", name, derrors.Conflict)
+		}
+		data, err := encodeAPIKey(k)
+		if err != nil {
+			return err
+		}
+		return b.Put(hashToken(key), data)
+	})
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	return k, key, nil
+}
+
+// Authenticate returns the principal authenticated by an API key.
+func (s *service) Authenticate(ctx context.Context, key string) (_ model.Principal, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Authenticate")
+	if !strings.HasPrefix(key, model.APIKeyPrefix) {
+		return model.Principal{}, errInvalidAPIKey
+	}
+	var k model.APIKey
+	err = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(apiKeyBucket)
+		if b == nil {
+			return errInvalidAPIKey
+		}
+		data := b.Get(hashToken(key))
+		if data == nil {
+			return errInvalidAPIKey
+		}
+		var err error
+		k, err = decodeAPIKey(data)
+		return err
+	})
+	if err != nil {
+		return model.Principal{}, err
+	}
+	return model.Principal{Name: k.Name, Role: k.Role, Tenant: k.Tenant}, nil
+}
+
+// APIKeys returns the API keys ordered by name, without the keys
+// themselves.
+func (s *service) APIKeys(ctx context.Context) (_ []model.APIKey, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.APIKeys")
+	var l []model.APIKey
+	err = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(apiKeyBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			k, err := decodeAPIKey(v)
+			if err != nil {
+				return err
+			}
+			l = append(l, k)
+			return nil
+		})
+	})
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l, err
+}
+
+// RevokeAPIKey deletes the API key of name.
+func (s *service) RevokeAPIKey(ctx context.Context, name string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RevokeAPIKey")
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(apiKeyBucket)
+		if b == nil {
+			return fmt.Errorf("API key %q %w", name, derrors.NotFound)
+		}
+		var hash []byte
+		err := b.ForEach(func(k, v []byte) error {
+			key, err := decodeAPIKey(v)
+			if err == nil && key.Name == name {
+				hash = append([]byte(nil), k...)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if hash == nil {
+			return fmt.Errorf("API key %q %w", name, derrors.NotFound)
+		}
+		return b.Delete(hash)
+	})
+}
+
+// deleteTenantAPIKeys deletes the API keys bound to tenant.
+func deleteTenantAPIKeys(tx *bolt.Tx, tenant string) error {
+	b := tx.Bucket(apiKeyBucket)
+	if b == nil {
+		return nil
+	}
+	var hashes [][]byte
+	err := b.ForEach(func(k, v []byte) error {
+		key, err := decodeAPIKey(v)
+		if err == nil && key.Tenant == tenant {
+			hashes = append(hashes, append([]byte(nil), k...))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if err := b.Delete(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashToken returns the SHA-256 hash a secret token is stored by.
+func hashToken(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+func encodeAPIKey(k model.APIKey) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(k); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeAPIKey(data []byte) (model.APIKey, error) {
+	var k model.APIKey
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&k)
+	return k, err
+}
@@ -0,0 +1,127 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceAPIKeys(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.CreateTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	_, bot, err := s.CreateAPIKey(ctx, "bot", model.RoleReader, "")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	_, ci, err := s.CreateAPIKey(ctx, "ci", model.RoleEditor, "acme")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	_, ops, err := s.CreateAPIKey(ctx, "ops", model.RoleAdmin, model.AllTenants)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, _, err := s.CreateAPIKey(ctx, "bot", model.RoleAdmin, ""); !errors.Is(err, derrors.Conflict) {
+		t.Fatalf("got = %v, want conflict", err)
+	}
+	if _, _, err := s.CreateAPIKey(ctx, "root", model.Role("root"), ""); err == nil {
+		t.Fatal("got = nil, want invalid role error")
+	}
+	if _, _, err := s.CreateAPIKey(ctx, "root", model.RoleAdmin, "missing"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+
+	var testCases = []struct {
+		name    string
+		key     string
+		want    model.Principal
+		wantErr bool
+	}{
+		{
+			name: "reader key",
+			key:  bot,
+			want: model.Principal{Name: "bot", Role: model.RoleReader, Tenant: model.DefaultTenant},
+		},
+		{
+			name: "editor key",
+			key:  ci,
+			want: model.Principal{Name: "ci", Role: model.RoleEditor, Tenant: "acme"},
+		},
+		{
+			name: "key of every tenant",
+			key:  ops,
+			want: model.Principal{Name: "ops", Role: model.RoleAdmin, Tenant: model.AllTenants},
+		},
+		{
+			name:    "unknown key",
+			key:     model.APIKeyPrefix + "unknown",
+			wantErr: true,
+		},
+		{
+			name:    "not a key",
+			key:     "bot",
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Authenticate(ctx, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected principal mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if err := s.RevokeAPIKey(ctx, "ci"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.RevokeAPIKey(ctx, "ci"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+	if _, err := s.Authenticate(ctx, ci); err == nil {
+		t.Fatal("got = nil, want error for a revoked key")
+	}
+	// The keys of a tenant are revoked along with it.
+	_, deploy, err := s.CreateAPIKey(ctx, "deploy", model.RoleEditor, "acme")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.DeleteTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.Authenticate(ctx, deploy); err == nil {
+		t.Fatal("got = nil, want error for the key of a deleted tenant")
+	}
+	got, err := s.APIKeys(ctx)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	want := []model.APIKey{
+		{Name: "bot", Role: model.RoleReader, Tenant: model.DefaultTenant, CreatedAt: now},
+		{Name: "ops", Role: model.RoleAdmin, Tenant: model.AllTenants, CreatedAt: now},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected API keys mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,218 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"sort"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/text"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// trigramBucket holds a nested bucket per trigram, mapping the keys of
+	// the questions containing it to where it appears.
+	trigramBucket = []byte("trigrams")
+	// trigramDocBucket maps the keys of the questions indexed to their
+	// trigrams.
+	trigramDocBucket = []byte("trigram_docs")
+)
+
+// Where a trigram appears in a question.
+const (
+	inKey byte = 1 << iota
+	inValue
+)
+
+// defaultAskThreshold is the confidence below which Ask finds no answer.
+const defaultAskThreshold = 0.3
+
+// WithAskThreshold sets the confidence, between 0 and 1, below which a
+// question doesn't answer a text given to Ask.
+func WithAskThreshold(threshold float64) Option {
+	return func(s *service) {
+		s.askThreshold = threshold
+	}
+}
+
+// trigramDoc is a question as seen by the trigram index.
+type trigramDoc struct {
+	Key   []string
+	Value []string
+}
+
+func indexTrigrams(tx *tenantTx, q *model.Question) error {
+	if err := unindexTrigrams(tx, q.Key); err != nil {
+		return err
+	}
+	tBucket := tx.Bucket(trigramBucket)
+	dBucket := tx.Bucket(trigramDocBucket)
+	if tBucket == nil || dBucket == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	doc := trigramDoc{
+		Key:   text.Trigrams(string(q.Key)),
+		Value: text.Trigrams(string(q.Value)),
+	}
+	where := map[string]byte{}
+	for _, t := range doc.Key {
+		where[t] |= inKey
+	}
+	for _, t := range doc.Value {
+		where[t] |= inValue
+	}
+	for t, w := range where {
+		b, err := tBucket.CreateBucketIfNotExists([]byte(t))
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(q.Key), []byte{w}); err != nil {
+			return err
+		}
+	}
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(doc); err != nil {
+		return err
+	}
+	return dBucket.Put([]byte(q.Key), data.Bytes())
+}
+
+func unindexTrigrams(tx *tenantTx, key model.Key) error {
+	tBucket := tx.Bucket(trigramBucket)
+	dBucket := tx.Bucket(trigramDocBucket)
+	if tBucket == nil || dBucket == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	doc, ok, err := getTrigramDoc(dBucket, []byte(key))
+	if err != nil || !ok {
+		return err
+	}
+	for _, t := range append(doc.Key, doc.Value...) {
+		b := tBucket.Bucket([]byte(t))
+		if b == nil {
+			continue
+		}
+		if err := b.Delete([]byte(key)); err != nil {
+			return err
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			if err := tBucket.DeleteBucket([]byte(t)); err != nil {
+				return err
+			}
+		}
+	}
+	return dBucket.Delete([]byte(key))
+}
+
+func getTrigramDoc(b *bolt.Bucket, key []byte) (trigramDoc, bool, error) {
+	var doc trigramDoc
+	data := b.Get(key)
+	if data == nil {
+		return doc, false, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return doc, false, err
+	}
+	return doc, true, nil
+}
+
+// Ask returns at most n questions answering the text of a question asked in
+// natural language, the best match first. The confidence of a match is the
+// share of the trigrams of the key found in the text, or the share of the
+// trigrams of the text found in the value, whichever is higher. Matches below
+// the threshold of the service, and questions outside of their validity
+// window, are left out, so no question answers when the list is empty, and
+// the text is recorded as a miss.
+func (s *service) Ask(ctx context.Context, query string, n int) (_ []model.Match, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Ask")
+	trigrams := text.Trigrams(query)
+	var matches []model.Match
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		tBucket := tx.Bucket(trigramBucket)
+		dBucket := tx.Bucket(trigramDocBucket)
+		if tBucket == nil || dBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		type common struct{ key, value int }
+		counts := map[string]*common{}
+		for _, t := range trigrams {
+			b := tBucket.Bucket([]byte(t))
+			if b == nil {
+				continue
+			}
+			err := b.ForEach(func(k, v []byte) error {
+				c, ok := counts[string(k)]
+				if !ok {
+					c = &common{}
+					counts[string(k)] = c
+				}
+				if v[0]&inKey != 0 {
+					c.key++
+				}
+				if v[0]&inValue != 0 {
+					c.value++
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for key, c := range counts {
+			doc, _, err := getTrigramDoc(dBucket, []byte(key))
+			if err != nil {
+				return err
+			}
+			var confidence float64
+			if len(doc.Key) > 0 {
+				confidence = float64(c.key) / float64(len(doc.Key))
+			}
+			if v := float64(c.value) / float64(len(trigrams)); v > confidence {
+				confidence = v
+			}
+			if confidence < s.askThreshold {
+				continue
+			}
+			matches = append(matches, model.Match{
+				Question:   model.Question{Key: model.Key(key)},
+				Confidence: confidence,
+			})
+		}
+		sort.Slice(matches, func(i, j int) bool {
+			if matches[i].Confidence != matches[j].Confidence {
+				return matches[i].Confidence > matches[j].Confidence
+			}
+			return matches[i].Question.Key < matches[j].Question.Key
+		})
+		var l []model.Match
+		for _, m := range matches {
+			if n > 0 && len(l) == n {
+				break
+			}
+			q, err := load(tx, m.Question.Key)
+			if err != nil {
+				return err
+			}
+			if !readable(ctx, q) {
+				continue
+			}
+			if q, err = visible(q); err != nil {
+				continue
+			}
+			m.Question = q
+			l = append(l, m)
+		}
+		matches = l
+		return nil
+	})
+	if err == nil && len(matches) == 0 {
+		err = s.recordMiss(ctx, searchMissKey(query), query)
+	}
+	return matches, err
+}
@@ -0,0 +1,83 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceAsk(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db, WithAskThreshold(0.4))
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, in := range []struct {
+		key   model.Key
+		value model.Value
+	}{
+		{"refund-policy", "Refunds are accepted within 30 days."},
+		{"refund-shipping", "Shipping costs are not refunded."},
+		{"opening-hours", "Open from 9 to 5."},
+		{"old-policy", "No refunds."},
+	} {
+		if _, err := s.New(ctx, in.key, in.value); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.Delete(ctx, "old-policy"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name  string
+		query string
+		n     int
+		want  []model.Key
+	}{
+		{
+			name:  "best match and alternatives",
+			query: "what is the refund polcy?",
+			want:  []model.Key{"refund-policy", "refund-shipping"},
+		},
+		{
+			name:  "limit",
+			query: "refund policy",
+			n:     1,
+			want:  []model.Key{"refund-policy"},
+		},
+		{
+			name:  "match on the value",
+			query: "are you open from 9?",
+			want:  []model.Key{"opening-hours"},
+		},
+		{
+			name:  "no answer",
+			query: "do you sell gift cards",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := s.Ask(ctx, tt.query, tt.n)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			var got []model.Key
+			for _, m := range matches {
+				got = append(got, m.Question.Key)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected matches mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
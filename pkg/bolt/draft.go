@@ -0,0 +1,84 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+)
+
+// errDraftRequired is returned when a principal who isn't an admin changes
+// the value of a question without a draft.
+var errDraftRequired = fmt.Errorf("the value can only change through a published draft: %w", derrors.Forbidden)
+
+// checkDraftRequired returns an error when events change the value of q or
+// one of its translations, or schedule a new value, outside of the review of
+// a draft and the principal of ctx isn't an admin. The contexts without
+// principal are allowed, as are the new questions. A question added again
+// over the log of a deleted one changes its value.
+func checkDraftRequired(ctx context.Context, q *model.Question, events []model.Event) error {
+	p, ok := model.PrincipalFromContext(ctx)
+	if !ok || p.Role.Allows(model.RoleAdmin) {
+		return nil
+	}
+	for _, ev := range events {
+		switch e := ev.(type) {
+		case model.QuestionUpdate, model.AnswerAccepted, model.ValueScheduled,
+			model.TranslationSet, model.TranslationRemoved:
+			return errDraftRequired
+		case model.QuestionAdded:
+			if len(q.History) > len(events) {
+				return errDraftRequired
+			}
+		case model.AnswerEdited:
+			if bytes.Equal(q.Accepted, e.AnswerID) {
+				return errDraftRequired
+			}
+		}
+	}
+	return nil
+}
+
+// ProposeDraft proposes value as the next value of the question stored at
+// key. The actor of ctx is the author of the draft.
+func (s *service) ProposeDraft(ctx context.Context, key model.Key, value model.Value) (_ model.Draft, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.ProposeDraft")
+	id := utils.NextID()
+	var d model.Draft
+	err = s.modify(ctx, key, func(q *model.Question) error {
+		if err := q.ProposeDraft(id, model.ActorFromContext(ctx), value); err != nil {
+			return err
+		}
+		d = *q.Draft(id)
+		return nil
+	})
+	return d, err
+}
+
+// ApproveDraft approves a draft in the name of the actor of ctx.
+func (s *service) ApproveDraft(ctx context.Context, key model.Key, id utils.ID, comment string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.ApproveDraft")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.ApproveDraft(id, model.ActorFromContext(ctx), comment)
+	})
+}
+
+// RejectDraft rejects a draft in the name of the actor of ctx.
+func (s *service) RejectDraft(ctx context.Context, key model.Key, id utils.ID, comment string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RejectDraft")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.RejectDraft(id, model.ActorFromContext(ctx), comment)
+	})
+}
+
+// PublishDraft makes an approved draft the value of the question stored at
+// key.
+func (s *service) PublishDraft(ctx context.Context, key model.Key, id utils.ID) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.PublishDraft")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.PublishDraft(id)
+	})
+}
@@ -0,0 +1,244 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceDrafts(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	var n int
+	utils.Generator = func() string {
+		n++
+		return fmt.Sprintf("test_id_%d", n)
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	ctx := context.Background()
+	john := model.NewContextWithActor(ctx, "john")
+	jane := model.NewContextWithActor(ctx, "jane")
+	if _, err := s.New(ctx, "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	first, err := s.ProposeDraft(john, "refunds", "Refunds take 10 days")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	second, err := s.ProposeDraft(john, "refunds", "Refunds take a month")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name      string
+		change    func() error
+		wantErr   bool
+		wantValue model.Value
+	}{
+		{
+			name:      "publish a proposed draft",
+			change:    func() error { return s.PublishDraft(jane, "refunds", first.ID) },
+			wantErr:   true,
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "approve own draft",
+			change:    func() error { return s.ApproveDraft(john, "refunds", first.ID, "lgtm") },
+			wantErr:   true,
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "anonymous approval",
+			change:    func() error { return s.ApproveDraft(ctx, "refunds", first.ID, "lgtm") },
+			wantErr:   true,
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "approve",
+			change:    func() error { return s.ApproveDraft(jane, "refunds", first.ID, "lgtm") },
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "reject",
+			change:    func() error { return s.RejectDraft(jane, "refunds", second.ID, "too long") },
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "publish a rejected draft",
+			change:    func() error { return s.PublishDraft(jane, "refunds", second.ID) },
+			wantErr:   true,
+			wantValue: "Refunds take 5 days",
+		},
+		{
+			name:      "publish",
+			change:    func() error { return s.PublishDraft(jane, "refunds", first.ID) },
+			wantValue: "Refunds take 10 days",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			q, err := s.Get(ctx, "refunds")
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			checkAsserts(t, q.Value, tt.wantValue)
+		})
+	}
+
+	q, err := s.Get(ctx, "refunds")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, len(q.Drafts), 0)
+	history, err := s.History(ctx, "refunds")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	var got []string
+	for _, e := range history {
+		got = append(got, fmt.Sprintf("%s %s", e.Event, e.Actor))
+	}
+	want := []string{
+		"draft_publish jane",
+		"draft_reject jane",
+		"draft_approve jane",
+		"draft_propose john",
+		"draft_propose john",
+		"add ",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected history mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServiceDraftRequired(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	var n int
+	utils.Generator = func() string {
+		n++
+		return fmt.Sprintf("test_id_%d", n)
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	ctx := context.Background()
+	editor := model.NewContextWithPrincipal(ctx, model.Principal{Name: "john", Role: model.RoleEditor})
+	admin := model.NewContextWithPrincipal(ctx, model.Principal{Name: "root", Role: model.RoleAdmin})
+	if _, err := s.New(editor, "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	accepted, err := s.AddAnswer(ctx, "refunds", "Refunds take 7 days")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	other, err := s.AddAnswer(editor, "refunds", "Refunds take a week")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.AcceptAnswer(ctx, "refunds", accepted.ID); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Translate(ctx, "refunds", "es", "Los reembolsos tardan 7 días"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name      string
+		change    func() error
+		wantErr   bool
+		wantValue model.Value
+	}{
+		{
+			name:      "editor update",
+			change:    func() error { return s.Update(editor, "refunds", "Refunds take 10 days") },
+			wantErr:   true,
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name:      "editor edits the accepted answer",
+			change:    func() error { return s.EditAnswer(editor, "refunds", accepted.ID, "Refunds take 10 days") },
+			wantErr:   true,
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name:      "editor edits another answer",
+			change:    func() error { return s.EditAnswer(editor, "refunds", other.ID, "Refunds take two weeks") },
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name:      "editor accepts an answer",
+			change:    func() error { return s.AcceptAnswer(editor, "refunds", other.ID) },
+			wantErr:   true,
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name: "editor schedules a value",
+			change: func() error {
+				return s.ScheduleValue(editor, "refunds", "Refunds take 10 days", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+			},
+			wantErr:   true,
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name:      "editor translates",
+			change:    func() error { return s.Translate(editor, "refunds", "fr", "Les remboursements prennent 10 jours") },
+			wantErr:   true,
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name:      "editor removes a translation",
+			change:    func() error { return s.RemoveTranslation(editor, "refunds", "es") },
+			wantErr:   true,
+			wantValue: "Refunds take 7 days",
+		},
+		{
+			name:      "admin update",
+			change:    func() error { return s.Update(admin, "refunds", "Refunds take 10 days") },
+			wantValue: "Refunds take 10 days",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, derrors.Forbidden) {
+				t.Errorf("got = %v, want forbidden", err)
+			}
+			q, err := s.Get(ctx, "refunds")
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			checkAsserts(t, q.Value, tt.wantValue)
+		})
+	}
+
+	// Adding a deleted question again changes the value of its log.
+	if err := s.Delete(editor, "refunds"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(editor, "refunds", "Refunds take a day"); !errors.Is(err, derrors.Forbidden) {
+		t.Errorf("got = %v, want forbidden", err)
+	}
+	if _, err := s.New(admin, "refunds", "Refunds take a day"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+}
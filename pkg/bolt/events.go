@@ -0,0 +1,152 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"sort"
+
+	"answer.io/pkg/model"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// appendEvents writes events at the end of the log of key. Every event gets
+// the next sequence of the bucket so the log is ordered by insertion, and is
+// wrapped in an envelope with the metadata found in ctx. version is the
+// version of the aggregate after the last event was applied. It returns the
+// sequence of the last event in the log.
+func appendEvents(ctx context.Context, tx *tenantTx, key model.Key, version int, events []model.Event) (uint64, error) {
+	eBucket := tx.Bucket(eventBucket)
+	if eBucket == nil {
+		return 0, errors.New("bucket doesn't exist")
+	}
+	b, err := eBucket.CreateBucketIfNotExists([]byte(key))
+	if err != nil {
+		return 0, err
+	}
+	for i, ev := range events {
+		seq, err := b.NextSequence()
+		if err != nil {
+			return 0, err
+		}
+		env := model.NewEnvelope(ctx, version-(len(events)-1-i), ev)
+		env.Sequence = seq
+		data, err := encodeEnvelope(env)
+		if err != nil {
+			return 0, err
+		}
+		if err := b.Put(itob(seq), data); err != nil {
+			return 0, err
+		}
+	}
+	return b.Sequence(), nil
+}
+
+// load rebuilds the question stored at key from its latest snapshot and
+// the events appended to the log after it.
+func load(tx *tenantTx, key model.Key) (model.Question, error) {
+	b, err := events(tx, key)
+	if err != nil {
+		return model.Question{}, err
+	}
+	return loadAt(tx, key, b.Sequence())
+}
+
+// loadAt rebuilds the question stored at key as it was after the event at
+// seq, starting from the latest snapshot taken at or before seq.
+func loadAt(tx *tenantTx, key model.Key, seq uint64) (model.Question, error) {
+	b, err := events(tx, key)
+	if err != nil {
+		return model.Question{}, err
+	}
+	q, from, err := snapshotAt(tx, key, seq)
+	if err != nil {
+		return q, err
+	}
+	var (
+		list    []model.Event
+		version = q.Version
+	)
+	c := b.Cursor()
+	for k, v := c.Seek(itob(from + 1)); k != nil && binary.BigEndian.Uint64(k) <= seq; k, v = c.Next() {
+		env, err := decodeEnvelope(v)
+		if err != nil {
+			return q, err
+		}
+		list = append(list, env.Event)
+		version = env.Version
+	}
+	if from == 0 {
+		q = *model.NewFromEvents(list)
+	} else {
+		for _, ev := range list {
+			q.On(ev, false)
+		}
+		q.History = list
+	}
+	// Replaying counts the creation of the question as a change, the
+	// envelopes keep the version the question had when it was saved.
+	q.Version = version
+	return q, nil
+}
+
+// searchEvents returns the sequence of the last event of key for which f is
+// true. f must be true for a prefix of the log, like the versions or the
+// timestamps of the envelopes up to a given one. It returns 0 when f is false
+// for every event.
+func searchEvents(tx *tenantTx, key model.Key, f func(model.Envelope) bool) (uint64, error) {
+	b, err := events(tx, key)
+	if err != nil {
+		return 0, err
+	}
+	var ferr error
+	n := sort.Search(int(b.Sequence()), func(i int) bool {
+		v := b.Get(itob(uint64(i + 1)))
+		env, err := decodeEnvelope(v)
+		if err != nil {
+			ferr = err
+			return true
+		}
+		return !f(env)
+	})
+	return uint64(n), ferr
+}
+
+// events returns the log bucket of key.
+func events(tx *tenantTx, key model.Key) (*bolt.Bucket, error) {
+	eBucket := tx.Bucket(eventBucket)
+	if eBucket == nil {
+		return nil, errors.New("bucket doesn't exist")
+	}
+	b := eBucket.Bucket([]byte(key))
+	if b == nil {
+		return nil, notFound(tx, key)
+	}
+	return b, nil
+}
+
+func encodeEnvelope(env model.Envelope) ([]byte, error) {
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(env); err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+func decodeEnvelope(data []byte) (model.Envelope, error) {
+	var env model.Envelope
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&env); err != nil {
+		return env, err
+	}
+	return env, nil
+}
+
+// itob returns an 8-byte big endian representation of v.
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,102 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+)
+
+// indexBuckets are the buckets of the indexes of the text, tags, references
+// and schedule of the questions, they can be rebuilt from the questions at
+// any time.
+var indexBuckets = [][]byte{
+	searchTermBucket,
+	searchDocBucket,
+	searchStatBucket,
+	trigramBucket,
+	trigramDocBucket,
+	tagBucket,
+	tagDocBucket,
+	dependentBucket,
+	referenceBucket,
+	scheduleBucket,
+}
+
+func createIndexBuckets(tx buckets) error {
+	for _, name := range indexBuckets {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// index adds q to the indexes, replacing the previous text, tags, references
+// and schedule of its key.
+func index(tx *tenantTx, q *model.Question) error {
+	if err := indexSearch(tx, q); err != nil {
+		return err
+	}
+	if err := indexTrigrams(tx, q); err != nil {
+		return err
+	}
+	if err := indexTags(tx, q); err != nil {
+		return err
+	}
+	if err := indexReferences(tx, q); err != nil {
+		return err
+	}
+	return indexSchedule(tx, q)
+}
+
+// unindex removes key from the indexes.
+func unindex(tx *tenantTx, key model.Key) error {
+	if err := unindexSearch(tx, key); err != nil {
+		return err
+	}
+	if err := unindexTrigrams(tx, key); err != nil {
+		return err
+	}
+	if err := unindexTags(tx, key); err != nil {
+		return err
+	}
+	if err := unindexReferences(tx, key); err != nil {
+		return err
+	}
+	return unindexSchedule(tx, key)
+}
+
+// Reindex rebuilds the indexes from the questions not deleted.
+func (s *service) Reindex(ctx context.Context) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Reindex")
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		for _, name := range indexBuckets {
+			if tx.Bucket(name) == nil {
+				continue
+			}
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		if err := createIndexBuckets(tx); err != nil {
+			return err
+		}
+		qBucket := tx.Bucket(questionBucket)
+		dBucket := tx.Bucket(deletedQuestionBucket)
+		if qBucket == nil || dBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		return qBucket.ForEach(func(k, _ []byte) error {
+			if data := dBucket.Get(k); len(data) > 0 {
+				return nil
+			}
+			q, err := load(tx, model.Key(k))
+			if err != nil {
+				return err
+			}
+			return index(tx, &q)
+		})
+	})
+}
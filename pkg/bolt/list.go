@@ -0,0 +1,189 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ListPage returns a page of the questions not deleted, inside of their
+// validity window and readable by the principal of ctx selected by opts, and
+// the token to get the next page. The token is empty on the last page.
+func (s *service) ListPage(ctx context.Context, opts model.ListOptions) (_ []model.Question, next string, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.ListPage")
+	after, err := decodeToken(opts.Token)
+	if err != nil {
+		return nil, "", err
+	}
+	var l []model.Question
+	now := utils.Clock()
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		qBucket := tx.Bucket(questionBucket)
+		dBucket := tx.Bucket(deletedQuestionBucket)
+		if qBucket == nil || dBucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+		r := newKeyRange(qBucket.Cursor(), opts, after)
+		for k := r.first(); k != nil; k = r.next() {
+			if data := dBucket.Get(k); len(data) > 0 {
+				continue
+			}
+			if !hasTags(tx, k, opts) {
+				continue
+			}
+			q, err := load(tx, model.Key(k))
+			if err != nil {
+				return err
+			}
+			if !q.Visible(now) || !readable(ctx, q) {
+				continue
+			}
+			if opts.Limit > 0 && len(l) == opts.Limit {
+				next = encodeToken(l[len(l)-1].Key)
+				return nil
+			}
+			l = append(l, q.Effective(now))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return l, next, nil
+}
+
+// keyRange walks the keys of a cursor selected by a model.ListOptions.
+type keyRange struct {
+	c       *bolt.Cursor
+	prefix  []byte
+	start   []byte
+	end     []byte
+	after   []byte
+	reverse bool
+}
+
+func newKeyRange(c *bolt.Cursor, opts model.ListOptions, after []byte) *keyRange {
+	r := &keyRange{
+		c:       c,
+		prefix:  keyBytes(opts.Prefix),
+		start:   keyBytes(opts.Start),
+		end:     keyBytes(opts.End),
+		after:   after,
+		reverse: opts.Reverse,
+	}
+	if len(r.prefix) > 0 && bytes.Compare(r.prefix, r.start) > 0 {
+		r.start = r.prefix
+	}
+	if end := prefixEnd(r.prefix); end != nil && (r.end == nil || bytes.Compare(end, r.end) < 0) {
+		r.end = end
+	}
+	return r
+}
+
+func (r *keyRange) first() []byte {
+	var k []byte
+	if r.reverse {
+		k = r.seekBefore(r.end)
+		if r.after != nil && (k == nil || bytes.Compare(r.after, k) <= 0) {
+			k = r.seekBefore(r.after)
+		}
+	} else {
+		k, _ = r.c.Seek(r.start)
+		if r.after != nil && (k == nil || bytes.Compare(r.after, k) >= 0) {
+			k, _ = r.c.Seek(r.after)
+			if bytes.Equal(k, r.after) {
+				k, _ = r.c.Next()
+			}
+		}
+	}
+	return r.check(k)
+}
+
+func (r *keyRange) next() []byte {
+	var k []byte
+	if r.reverse {
+		k, _ = r.c.Prev()
+	} else {
+		k, _ = r.c.Next()
+	}
+	return r.check(k)
+}
+
+// seekBefore moves the cursor to the last key lower than bound, or to the
+// last key when bound is nil.
+func (r *keyRange) seekBefore(bound []byte) []byte {
+	if bound == nil {
+		k, _ := r.c.Last()
+		return k
+	}
+	if k, _ := r.c.Seek(bound); k == nil {
+		k, _ = r.c.Last()
+		if k != nil && bytes.Compare(k, bound) >= 0 {
+			k, _ = r.c.Prev()
+		}
+		return k
+	}
+	k, _ := r.c.Prev()
+	return k
+}
+
+// check returns k when it is inside the range, nil otherwise.
+func (r *keyRange) check(k []byte) []byte {
+	if k == nil {
+		return nil
+	}
+	if r.end != nil && bytes.Compare(k, r.end) >= 0 {
+		return nil
+	}
+	if bytes.Compare(k, r.start) < 0 {
+		return nil
+	}
+	if !bytes.HasPrefix(k, r.prefix) {
+		return nil
+	}
+	return k
+}
+
+// keyBytes returns key as bytes, nil when it is empty.
+func keyBytes(key model.Key) []byte {
+	if key == "" {
+		return nil
+	}
+	return []byte(key)
+}
+
+// prefixEnd returns the lowest key greater than every key starting with
+// prefix, or nil when there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func encodeToken(key model.Key) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeToken(token string) ([]byte, error) {
+	if token == "" {
+		return nil, nil
+	}
+	after, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(after) == 0 {
+		return nil, errors.New("invalid token")
+	}
+	return after, nil
+}
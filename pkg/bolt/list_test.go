@@ -0,0 +1,99 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceListPage(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, key := range []model.Key{"a", "b1", "b2", "b3", "c", "d"} {
+		if _, err := s.New(ctx, key, "value"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.Delete(ctx, "c"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name string
+		opts model.ListOptions
+		want [][]model.Key
+	}{
+		{
+			name: "pages",
+			opts: model.ListOptions{Limit: 2},
+			want: [][]model.Key{{"a", "b1"}, {"b2", "b3"}, {"d"}},
+		},
+		{
+			name: "reverse pages",
+			opts: model.ListOptions{Limit: 3, Reverse: true},
+			want: [][]model.Key{{"d", "b3", "b2"}, {"b1", "a"}},
+		},
+		{
+			name: "prefix",
+			opts: model.ListOptions{Limit: 2, Prefix: "b"},
+			want: [][]model.Key{{"b1", "b2"}, {"b3"}},
+		},
+		{
+			name: "reverse prefix",
+			opts: model.ListOptions{Prefix: "b", Reverse: true},
+			want: [][]model.Key{{"b3", "b2", "b1"}},
+		},
+		{
+			name: "range",
+			opts: model.ListOptions{Start: "b2", End: "d"},
+			want: [][]model.Key{{"b2", "b3"}},
+		},
+		{
+			name: "reverse range",
+			opts: model.ListOptions{Limit: 1, Start: "b2", End: "d", Reverse: true},
+			want: [][]model.Key{{"b3"}, {"b2"}},
+		},
+		{
+			name: "empty range",
+			opts: model.ListOptions{Prefix: "x"},
+			want: [][]model.Key{nil},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]model.Key
+			opts := tt.opts
+			for {
+				l, next, err := s.ListPage(ctx, opts)
+				if err != nil {
+					t.Fatalf("got = %v, want nil", err)
+				}
+				var keys []model.Key
+				for _, q := range l {
+					keys = append(keys, q.Key)
+				}
+				got = append(got, keys)
+				if next == "" {
+					break
+				}
+				opts.Token = next
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/text"
+)
+
+// Translate sets the value in locale of the question stored at key.
+func (s *service) Translate(ctx context.Context, key model.Key, locale string, value model.Value) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Translate")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.Translate(locale, value)
+	})
+}
+
+func (s *service) RemoveTranslation(ctx context.Context, key model.Key, locale string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RemoveTranslation")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.RemoveTranslation(locale)
+	})
+}
+
+// MissingTranslations returns the questions not deleted that aren't
+// translated to some of locales, ordered by key.
+func (s *service) MissingTranslations(ctx context.Context, locales []string) (_ []model.MissingTranslation, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.MissingTranslations")
+	for i, locale := range locales {
+		if locales[i], err = model.ParseLocale(locale); err != nil {
+			return nil, err
+		}
+	}
+	var l []model.MissingTranslation
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		qBucket := tx.Bucket(questionBucket)
+		dBucket := tx.Bucket(deletedQuestionBucket)
+		if qBucket == nil || dBucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+		return qBucket.ForEach(func(k, _ []byte) error {
+			if data := dBucket.Get(k); len(data) > 0 {
+				return nil
+			}
+			q, err := load(tx, model.Key(k))
+			if err != nil {
+				return err
+			}
+			if !readable(ctx, q) {
+				return nil
+			}
+			m := model.MissingTranslation{Key: q.Key}
+			for _, locale := range locales {
+				if _, ok := q.Translations[locale]; !ok {
+					m.Locales = append(m.Locales, locale)
+				}
+			}
+			if len(m.Locales) > 0 {
+				l = append(l, m)
+			}
+			return nil
+		})
+	})
+	return l, err
+}
+
+// OutdatedTranslations returns the translations in locale, or in any locale
+// when it is empty, made from a default value changed since. Each one comes
+// with the diff of the default value since it was translated.
+func (s *service) OutdatedTranslations(ctx context.Context, locale string) (_ []model.OutdatedTranslation, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.OutdatedTranslations")
+	if locale != "" {
+		if locale, err = model.ParseLocale(locale); err != nil {
+			return nil, err
+		}
+	}
+	var l []model.OutdatedTranslation
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		qBucket := tx.Bucket(questionBucket)
+		dBucket := tx.Bucket(deletedQuestionBucket)
+		if qBucket == nil || dBucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+		return qBucket.ForEach(func(k, _ []byte) error {
+			if data := dBucket.Get(k); len(data) > 0 {
+				return nil
+			}
+			q, err := load(tx, model.Key(k))
+			if err != nil {
+				return err
+			}
+			if !readable(ctx, q) {
+				return nil
+			}
+			locales := make([]string, 0, len(q.Translations))
+			for l, t := range q.Translations {
+				if t.Outdated && (locale == "" || l == locale) {
+					locales = append(locales, l)
+				}
+			}
+			sort.Strings(locales)
+			for _, locale := range locales {
+				t := q.Translations[locale]
+				source, err := getAt(tx, q.Key, t.SourceVersion)
+				if err != nil {
+					return err
+				}
+				l = append(l, model.OutdatedTranslation{
+					Key:         q.Key,
+					Locale:      locale,
+					Translation: t,
+					Source:      q.Value,
+					Diff:        text.Diff(string(source.Value), string(q.Value)),
+				})
+			}
+			return nil
+		})
+	})
+	return l, err
+}
@@ -0,0 +1,133 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/text"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceMissingTranslations(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	translations := map[model.Key][]string{
+		"refunds":  {"es", "fr"},
+		"shipping": {"es-MX"},
+		"privacy":  {"ES"},
+	}
+	for key, locales := range translations {
+		if _, err := s.New(ctx, key, "value"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		for _, locale := range locales {
+			if err := s.Translate(ctx, key, locale, "valor"); err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+		}
+	}
+	if err := s.RemoveTranslation(ctx, "refunds", "fr"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Translate(ctx, "refunds", "not a locale", "valor"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+
+	want := []model.MissingTranslation{
+		{Key: "privacy", Locales: []string{"fr"}},
+		{Key: "refunds", Locales: []string{"fr"}},
+		{Key: "shipping", Locales: []string{"es", "fr"}},
+	}
+	got, err := s.MissingTranslations(ctx, []string{"es", "fr"})
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected missing translations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServiceOutdatedTranslations(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(ctx, "refunds", "Refunds take 5 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Translate(ctx, "refunds", "es", "Los reembolsos tardan 5 días"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Update(ctx, "refunds", "Refunds take 10 days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Translate(ctx, "refunds", "fr", "Les remboursements prennent 10 jours"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Update(ctx, "refunds", "Refunds take 10 business days"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Translate(ctx, "refunds", "fr", "Les remboursements prennent 10 jours ouvrés"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name   string
+		locale string
+		want   []model.OutdatedTranslation
+	}{
+		{
+			name: "any locale",
+			want: []model.OutdatedTranslation{
+				{
+					Key:    "refunds",
+					Locale: "es",
+					Translation: model.Translation{
+						Value:         "Los reembolsos tardan 5 días",
+						SourceVersion: 0,
+						Outdated:      true,
+					},
+					Source: "Refunds take 10 business days",
+					Diff: []text.Edit{
+						{Op: text.Equal, Text: "Refunds take"},
+						{Op: text.Delete, Text: "5"},
+						{Op: text.Insert, Text: "10 business"},
+						{Op: text.Equal, Text: "days"},
+					},
+				},
+			},
+		},
+		{
+			name:   "up to date locale",
+			locale: "fr",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.OutdatedTranslations(ctx, tt.locale)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected outdated translations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
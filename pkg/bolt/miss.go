@@ -0,0 +1,149 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"sort"
+	"strings"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/text"
+	"answer.io/pkg/utils"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// maxMissSamples is the number of sources kept for a miss.
+	maxMissSamples = 5
+
+	// defaultMaxMisses is the number of distinct misses recorded per
+	// tenant.
+	defaultMaxMisses = 10000
+
+	// maxMissKeyLength is the length of the longest key recorded as a miss,
+	// and maxMissSampleLength the one of the longest sample kept.
+	maxMissKeyLength    = 128
+	maxMissSampleLength = 256
+)
+
+// WithMaxMisses sets the number of distinct misses recorded per tenant, the
+// keys missed after are not recorded until some are dismissed.
+func WithMaxMisses(n int) Option {
+	return func(s *service) {
+		s.maxMisses = n
+	}
+}
+
+// recordMiss counts a lookup of key that found no question.
+func (s *service) recordMiss(ctx context.Context, key model.Key, source string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.recordMiss")
+	if key == "" || len(key) > maxMissKeyLength {
+		return nil
+	}
+	if len(source) > maxMissSampleLength {
+		source = strings.ToValidUTF8(source[:maxMissSampleLength], "")
+	}
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		b := tx.Bucket(missBucket)
+		if b == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		m, ok, err := getMiss(b, key)
+		if err != nil {
+			return err
+		}
+		if !ok && b.Stats().KeyN >= s.maxMisses {
+			return nil
+		}
+		now := utils.Clock().UTC()
+		if m.Count == 0 {
+			m.Key = key
+			m.FirstSeen = now
+		}
+		m.Count++
+		m.LastSeen = now
+		if source != "" {
+			m.Samples = append(m.Samples, source)
+			if len(m.Samples) > maxMissSamples {
+				m.Samples = m.Samples[len(m.Samples)-maxMissSamples:]
+			}
+		}
+		var data bytes.Buffer
+		if err := gob.NewEncoder(&data).Encode(m); err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data.Bytes())
+	})
+}
+
+// searchMissKey returns the key a search without results is recorded at.
+func searchMissKey(query string) model.Key {
+	return model.Key(strings.Join(text.Tokenize(query), "-"))
+}
+
+func getMiss(b *bolt.Bucket, key model.Key) (model.Miss, bool, error) {
+	var m model.Miss
+	data := b.Get([]byte(key))
+	if data == nil {
+		return m, false, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&m); err != nil {
+		return m, false, err
+	}
+	return m, true, nil
+}
+
+// Misses returns at most limit misses, the most requested first.
+func (s *service) Misses(ctx context.Context, limit int) (_ []model.Miss, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Misses")
+	var l []model.Miss
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		b := tx.Bucket(missBucket)
+		if b == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			m, _, err := getMiss(b, model.Key(k))
+			if err != nil {
+				return err
+			}
+			l = append(l, m)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		if l[i].Count != l[j].Count {
+			return l[i].Count > l[j].Count
+		}
+		return l[i].LastSeen.After(l[j].LastSeen)
+	})
+	if limit > 0 && len(l) > limit {
+		l = l[:limit]
+	}
+	return l, nil
+}
+
+// DismissMiss forgets the miss recorded at key.
+func (s *service) DismissMiss(ctx context.Context, key model.Key) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.DismissMiss")
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		b := tx.Bucket(missBucket)
+		if b == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		if _, ok, err := getMiss(b, key); err != nil || !ok {
+			if err == nil {
+				err = errors.New("miss not found")
+			}
+			return err
+		}
+		return b.Delete([]byte(key))
+	})
+}
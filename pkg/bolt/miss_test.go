@@ -0,0 +1,103 @@
+package bolt
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceMisses(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+
+	s, err := NewService(db, WithMaxMisses(3))
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(ctx, "name", "John"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	lookups := []struct {
+		key    model.Key
+		source string
+	}{
+		{"refunds", "https://example.com/faq"},
+		{"name", ""},
+		{"shipping", ""},
+		{"refunds", "support-bot"},
+	}
+	for _, l := range lookups {
+		now = now.Add(time.Minute)
+		_, _ = s.Get(model.NewContextWithSource(ctx, l.source), l.key)
+	}
+	// The keys too long aren't recorded.
+	_, _ = s.Get(ctx, model.Key(strings.Repeat("a", maxMissKeyLength+1)))
+	now = now.Add(time.Minute)
+	if _, err := s.Search(ctx, "Gift cards?", 10); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	// Past the maximum, the keys not missed yet aren't recorded.
+	_, _ = s.Get(ctx, "returns")
+
+	want := []model.Miss{
+		{
+			Key:       "refunds",
+			Count:     2,
+			FirstSeen: start.Add(time.Minute),
+			LastSeen:  start.Add(4 * time.Minute),
+			Samples:   []string{"https://example.com/faq", "support-bot"},
+		},
+		{
+			Key:       "gift-cards",
+			Count:     1,
+			FirstSeen: start.Add(5 * time.Minute),
+			LastSeen:  start.Add(5 * time.Minute),
+			Samples:   []string{"Gift cards?"},
+		},
+		{
+			Key:       "shipping",
+			Count:     1,
+			FirstSeen: start.Add(3 * time.Minute),
+			LastSeen:  start.Add(3 * time.Minute),
+		},
+	}
+	got, err := s.Misses(ctx, 0)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected misses mismatch (-want +got):\n%s", diff)
+	}
+
+	// Answering a miss or dismissing it removes it from the queue.
+	if _, err := s.New(ctx, "refunds", "Within 30 days."); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.DismissMiss(ctx, "shipping"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.DismissMiss(ctx, "shipping"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	got, err = s.Misses(ctx, 0)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want[1:2], got); diff != "" {
+		t.Errorf("unexpected misses mismatch (-want +got):\n%s", diff)
+	}
+}
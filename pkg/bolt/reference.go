@@ -0,0 +1,260 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	// dependentBucket holds a bucket per referenced key with the keys of the
+	// questions referencing it.
+	dependentBucket = []byte("dependents")
+	// referenceBucket holds the keys referenced by every key, to unindex
+	// them.
+	referenceBucket = []byte("references")
+)
+
+// referenceSep separates the keys referenced in the referenceBucket.
+var referenceSep = []byte{0}
+
+const (
+	// maxTransclusionDepth bounds the nesting of the references resolved.
+	maxTransclusionDepth = 10
+	// maxTransclusions bounds the references resolved for a value.
+	maxTransclusions = 100
+)
+
+// indexReferences records the keys referenced by q, replacing the previous
+// references of its key.
+func indexReferences(tx *tenantTx, q *model.Question) error {
+	if err := unindexReferences(tx, q.Key); err != nil {
+		return err
+	}
+	refs := q.References()
+	if len(refs) == 0 {
+		return nil
+	}
+	dBucket := tx.Bucket(dependentBucket)
+	rBucket := tx.Bucket(referenceBucket)
+	if dBucket == nil || rBucket == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	keys := make([][]byte, len(refs))
+	for i, ref := range refs {
+		keys[i] = []byte(ref)
+		b, err := dBucket.CreateBucketIfNotExists(keys[i])
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(q.Key), q.Id); err != nil {
+			return err
+		}
+	}
+	return rBucket.Put([]byte(q.Key), bytes.Join(keys, referenceSep))
+}
+
+// unindexReferences removes the references of key.
+func unindexReferences(tx *tenantTx, key model.Key) error {
+	dBucket := tx.Bucket(dependentBucket)
+	rBucket := tx.Bucket(referenceBucket)
+	if dBucket == nil || rBucket == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	for _, ref := range references(tx, key) {
+		b := dBucket.Bucket([]byte(ref))
+		if b == nil {
+			continue
+		}
+		if err := b.Delete([]byte(key)); err != nil {
+			return err
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			if err := dBucket.DeleteBucket([]byte(ref)); err != nil {
+				return err
+			}
+		}
+	}
+	return rBucket.Delete([]byte(key))
+}
+
+// references returns the keys referenced by key as indexed.
+func references(tx *tenantTx, key model.Key) []model.Key {
+	b := tx.Bucket(referenceBucket)
+	if b == nil {
+		return nil
+	}
+	data := b.Get([]byte(key))
+	if len(data) == 0 {
+		return nil
+	}
+	var refs []model.Key
+	for _, ref := range bytes.Split(data, referenceSep) {
+		refs = append(refs, model.Key(ref))
+	}
+	return refs
+}
+
+// resolve returns the key a reference to key points to, following the
+// aliases and the renames.
+func resolve(tx *tenantTx, key model.Key) model.Key {
+	if live(tx, key) {
+		return key
+	}
+	if to, ok := aliasOf(tx, key); ok {
+		return to
+	}
+	var moved *model.MovedError
+	if data := tx.Bucket(questionBucket).Get([]byte(key)); len(data) == 0 && errors.As(notFound(tx, key), &moved) {
+		return moved.Key
+	}
+	return key
+}
+
+// checkCycle returns an error when q references itself, directly or
+// through the questions it references. The values scheduled or proposed as
+// drafts by events are checked too, they would become the value of q.
+func checkCycle(tx *tenantTx, q *model.Question, events []model.Event) error {
+	visited := map[model.Key]bool{}
+	var visit func(key model.Key, path []model.Key) error
+	visit = func(key model.Key, path []model.Key) error {
+		key = resolve(tx, key)
+		path = append(path, key)
+		if key == q.Key {
+			keys := make([]string, len(path))
+			for i, k := range path {
+				keys[i] = string(k)
+			}
+			return fmt.Errorf("reference cycle %s", strings.Join(keys, " -> "))
+		}
+		if visited[key] {
+			return nil
+		}
+		visited[key] = true
+		for _, ref := range references(tx, key) {
+			if err := visit(ref, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	refs := q.References()
+	for _, ev := range events {
+		switch e := ev.(type) {
+		case model.ValueScheduled:
+			refs = append(refs, model.References(e.Value)...)
+		case model.DraftCreated:
+			refs = append(refs, model.References(e.Value)...)
+		}
+	}
+	for _, ref := range refs {
+		if err := visit(ref, []model.Key{q.Key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dependents returns the keys of the questions readable by the principal of
+// ctx referencing key, or one of the keys it was renamed from, sorted.
+func (s *service) Dependents(ctx context.Context, key model.Key) (_ []model.Key, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Dependents")
+	var l []model.Key
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		dBucket := tx.Bucket(dependentBucket)
+		rBucket := tx.Bucket(redirectBucket)
+		if dBucket == nil || rBucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+		keys := [][]byte{[]byte(key)}
+		err := rBucket.ForEach(func(k, v []byte) error {
+			if bytes.Equal(v, []byte(key)) {
+				keys = append(keys, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		seen := map[model.Key]bool{}
+		for _, k := range keys {
+			b := dBucket.Bucket(k)
+			if b == nil {
+				continue
+			}
+			err := b.ForEach(func(k, _ []byte) error {
+				if seen[model.Key(k)] {
+					return nil
+				}
+				seen[model.Key(k)] = true
+				q, err := load(tx, model.Key(k))
+				if err != nil {
+					return err
+				}
+				if readable(ctx, q) {
+					l = append(l, model.Key(k))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+	return l, err
+}
+
+// Transclude replaces the references inside value with the current value
+// of the questions referenced, in the first of prefs they are translated
+// to, def being the locale of the default values. The questions referenced
+// that are templates are rendered with vars. When template is true, value
+// is a template and the values included are escaped to render as is. The
+// references to missing questions, to questions outside of their validity
+// window, to questions the principal of ctx isn't allowed to read, or past
+// the maximum number of references resolved, are kept as is.
+func (s *service) Transclude(ctx context.Context, value model.Value, template bool, vars map[string]string, prefs []language.Tag, def language.Tag) (_ model.Value, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Transclude")
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		var n int
+		var include func(depth int, template bool) func(key model.Key) (model.Value, bool)
+		include = func(depth int, template bool) func(key model.Key) (model.Value, bool) {
+			return func(key model.Key) (model.Value, bool) {
+				if depth >= maxTransclusionDepth || n >= maxTransclusions {
+					return "", false
+				}
+				n++
+				q, err := get(tx, resolve(tx, key))
+				if err != nil || !readable(ctx, q) {
+					return "", false
+				}
+				if q, err = visible(q); err != nil {
+					return "", false
+				}
+				v, _ := q.Localize(prefs, def)
+				v = model.Transclude(v, include(depth+1, q.Template))
+				if q.Template {
+					if v, err = model.Render(v, vars); err != nil {
+						return "", false
+					}
+				}
+				if template {
+					v = model.Literal(v)
+				}
+				return v, true
+			}
+		}
+		value = model.Transclude(value, include(0, template))
+		return nil
+	})
+	return value, err
+}
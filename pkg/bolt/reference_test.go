@@ -0,0 +1,181 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestServiceReferences(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	questions := []struct {
+		key   model.Key
+		value model.Value
+	}{
+		{"email", "support@example.com"},
+		{"contact", "Write to [[email]]"},
+		{"refunds", "Refunds take 5 days. [[contact]]"},
+		{"invoices", "Ask [[email]] or see [[missing]]"},
+	}
+	for _, q := range questions {
+		if _, err := s.New(ctx, q.key, q.value); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.Translate(ctx, "contact", "es", "Escribe a [[email]]"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name    string
+		key     model.Key
+		value   model.Value
+		wantErr bool
+	}{
+		{
+			name:    "self reference",
+			key:     "email",
+			value:   "[[email]]",
+			wantErr: true,
+		},
+		{
+			name:    "indirect cycle",
+			key:     "email",
+			value:   "See [[refunds]]",
+			wantErr: true,
+		},
+		{
+			name:  "reference without cycle",
+			key:   "email",
+			value: "help@example.com, see [[missing]]",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Update(ctx, tt.key, tt.value); (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := s.Update(ctx, "email", "help@example.com"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Rename(ctx, "email", "support-email"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	got, err := s.Dependents(ctx, "support-email")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff([]model.Key{"contact", "invoices"}, got); diff != "" {
+		t.Errorf("unexpected dependents mismatch (-want +got):\n%s", diff)
+	}
+
+	q, err := s.Get(ctx, "refunds")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, tt := range []struct {
+		prefs []language.Tag
+		want  model.Value
+	}{
+		{want: "Refunds take 5 days. Write to help@example.com"},
+		{prefs: []language.Tag{language.Spanish}, want: "Refunds take 5 days. Escribe a help@example.com"},
+	} {
+		v, err := s.Transclude(ctx, q.Value, false, nil, tt.prefs, language.Und)
+		if err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		checkAsserts(t, v, tt.want)
+	}
+	q, err = s.Get(ctx, "invoices")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	v, err := s.Transclude(ctx, q.Value, false, nil, nil, language.Und)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, v, model.Value("Ask help@example.com or see [[missing]]"))
+}
+
+func TestServiceTranscludeTemplates(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	var n int
+	utils.Generator = func() string {
+		n++
+		return fmt.Sprintf("test_id_%d", n)
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, q := range []struct {
+		key   model.Key
+		value model.Value
+	}{
+		{"syntax", "Write {{.name}} in a template"},
+		{"signature", "Regards, {{.name}}"},
+		{"many", model.Value(strings.Repeat("[[signature]]", maxTransclusions+1))},
+	} {
+		if _, err := s.New(ctx, q.key, q.value); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.SetTemplate(ctx, "signature", true); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	vars := map[string]string{"name": "Jane"}
+
+	var testCases = []struct {
+		name     string
+		value    model.Value
+		template bool
+		want     model.Value
+	}{
+		{
+			name:  "template included",
+			value: "See [[syntax]]. [[signature]]",
+			want:  "See Write {{.name}} in a template. Regards, Jane",
+		},
+		{
+			name:     "included in a template",
+			value:    "See [[syntax]]. [[signature]]",
+			template: true,
+			want:     `See {{"Write {{.name}} in a template"}}. {{"Regards, Jane"}}`,
+		},
+		{
+			name:  "too many references",
+			value: "[[many]]",
+			want:  model.Value(strings.Repeat("Regards, Jane", maxTransclusions-1) + "[[signature]][[signature]]"),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Transclude(ctx, tt.value, tt.template, vars, nil, language.Und)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			checkAsserts(t, got, tt.want)
+		})
+	}
+}
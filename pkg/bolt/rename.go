@@ -0,0 +1,121 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Rename moves the question stored at old to key, keeping its ID and its
+// history. old is left as a redirect to key. old can't be an alias, and key
+// is no longer missing.
+func (s *service) Rename(ctx context.Context, old, key model.Key) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Rename")
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		if to, ok := aliasOf(tx, old); ok {
+			return fmt.Errorf("key %q is an alias of %q: %w", old, to, derrors.Conflict)
+		}
+		q, err := get(tx, old)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, q, model.PermissionWrite); err != nil {
+			return err
+		}
+		qBucket := tx.Bucket(questionBucket)
+		rBucket := tx.Bucket(redirectBucket)
+		if qBucket == nil || rBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		if data := qBucket.Get([]byte(key)); len(data) > 0 {
+			return errors.New("key already exist")
+		}
+		if to, ok := aliasOf(tx, key); ok {
+			return fmt.Errorf("key %q is an alias of %q: %w", key, to, derrors.Conflict)
+		}
+		n := len(q.History)
+		if err := q.Rename(key); err != nil {
+			return err
+		}
+		for _, name := range [][]byte{eventBucket, snapshotBucket} {
+			if err := moveBucket(tx.Bucket(name), old, key); err != nil {
+				return err
+			}
+		}
+		if err := qBucket.Delete([]byte(old)); err != nil {
+			return err
+		}
+		if err := unindex(tx, old); err != nil {
+			return err
+		}
+		if err := s.put(ctx, tx, &q, q.History[n:]); err != nil {
+			return err
+		}
+		if err := tx.Bucket(missBucket).Delete([]byte(key)); err != nil {
+			return err
+		}
+		return redirect(rBucket, old, key)
+	})
+}
+
+// redirect points old to key, along with the keys pointing to old.
+func redirect(b *bolt.Bucket, old, key model.Key) error {
+	var from [][]byte
+	err := b.ForEach(func(k, v []byte) error {
+		if bytes.Equal(v, []byte(old)) {
+			from = append(from, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range append(from, []byte(old)) {
+		if err := b.Put(k, []byte(key)); err != nil {
+			return err
+		}
+	}
+	return b.Delete([]byte(key))
+}
+
+// moveBucket moves the nested bucket old of b to key, with its sequence.
+func moveBucket(b *bolt.Bucket, old, key model.Key) error {
+	if b == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	src := b.Bucket([]byte(old))
+	if src == nil {
+		return nil
+	}
+	dst, err := b.CreateBucket([]byte(key))
+	if err != nil {
+		return fmt.Errorf("move %q: %w", old, err)
+	}
+	err = src.ForEach(func(k, v []byte) error {
+		return dst.Put(k, v)
+	})
+	if err != nil {
+		return err
+	}
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return b.DeleteBucket([]byte(old))
+}
+
+// notFound returns the error for a question missing at key, telling where
+// it moved when it was renamed.
+func notFound(tx *tenantTx, key model.Key) error {
+	if b := tx.Bucket(redirectBucket); b != nil {
+		if to := b.Get([]byte(key)); len(to) > 0 {
+			return &model.MovedError{Key: model.Key(to)}
+		}
+	}
+	return errQuestionNotFound
+}
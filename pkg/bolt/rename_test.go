@@ -0,0 +1,180 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceRename(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, key := range []model.Key{"name", "last_name"} {
+		if _, err := s.New(ctx, key, "John"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.AddAlias(ctx, "last_name", "surname"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.recordMiss(ctx, "given_name", ""); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	var testCases = []struct {
+		name    string
+		old     model.Key
+		key     model.Key
+		wantErr bool
+	}{
+		{
+			name: "rename question",
+			old:  "name",
+			key:  "first_name",
+		},
+		{
+			name: "rename renamed question",
+			old:  "first_name",
+			key:  "given_name",
+		},
+		{
+			name:    "rename to an existing key",
+			old:     "given_name",
+			key:     "last_name",
+			wantErr: true,
+		},
+		{
+			name:    "rename from a moved key",
+			old:     "name",
+			key:     "other_name",
+			wantErr: true,
+		},
+		{
+			name:    "rename from an alias",
+			old:     "surname",
+			key:     "family_name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Rename(ctx, tt.old, tt.key); (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want nil", err)
+			}
+		})
+	}
+
+	want := model.Question{
+		Id:    utils.NextID(),
+		Key:   "given_name",
+		Value: "John",
+		History: []model.Event{
+			model.QuestionAdded{ID: utils.NextID(), Key: "name", Value: "John"},
+			model.QuestionKeyChanged{ID: utils.NextID(), OldKey: "name", NewKey: "first_name"},
+			model.QuestionKeyChanged{ID: utils.NextID(), OldKey: "first_name", NewKey: "given_name"},
+		},
+		Version: 2,
+	}
+	got, err := s.Get(ctx, "given_name")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected question mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, key := range []model.Key{"name", "first_name"} {
+		_, err := s.Get(ctx, key)
+		var moved *model.MovedError
+		if !errors.As(err, &moved) {
+			t.Fatalf("got = %v, want moved error", err)
+		}
+		checkAsserts(t, moved.Key, model.Key("given_name"))
+		if err := s.Update(ctx, key, "Jane"); !errors.As(err, &moved) {
+			t.Fatalf("got = %v, want moved error", err)
+		}
+	}
+	if _, err := s.Get(ctx, "last_name"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	// The new key is no longer missing.
+	misses, err := s.Misses(ctx, 10)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, len(misses), 0)
+}
+
+func TestServiceGetByID(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	var n int
+	utils.Generator = func() string {
+		n++
+		return fmt.Sprintf("test_id_%d", n)
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	name, err := s.New(ctx, "name", "John")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	lastName, err := s.New(ctx, "last_name", "Doe")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Rename(ctx, "name", "first_name"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Delete(ctx, "last_name"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	var testCases = []struct {
+		name    string
+		id      utils.ID
+		wantKey model.Key
+		wantErr bool
+	}{
+		{
+			name:    "get renamed question",
+			id:      name.Id,
+			wantKey: "first_name",
+		},
+		{
+			name:    "get deleted question",
+			id:      lastName.Id,
+			wantErr: true,
+		},
+		{
+			name:    "get unknown id",
+			id:      utils.ID("unknown"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetByID(ctx, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			checkAsserts(t, got.Key, tt.wantKey)
+		})
+	}
+}
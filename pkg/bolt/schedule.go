@@ -0,0 +1,126 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+)
+
+// scheduleBucket holds the keys of the questions waiting for a scheduled
+// value or for a side of their validity window to pass.
+var scheduleBucket = []byte("schedule")
+
+// errQuestionNotVisible is returned for a question outside of its validity
+// window.
+var errQuestionNotVisible = fmt.Errorf("question outside of its validity window: %w", derrors.NotFound)
+
+// indexSchedule adds the key of q to the schedule when it is pending.
+func indexSchedule(tx *tenantTx, q *model.Question) error {
+	if !q.Pending() {
+		return unindexSchedule(tx, q.Key)
+	}
+	b := tx.Bucket(scheduleBucket)
+	if b == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	return b.Put([]byte(q.Key), q.Id)
+}
+
+func unindexSchedule(tx *tenantTx, key model.Key) error {
+	b := tx.Bucket(scheduleBucket)
+	if b == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	return b.Delete([]byte(key))
+}
+
+// visible returns q as it is now, or an error when it is outside of its
+// validity window.
+func visible(q model.Question) (model.Question, error) {
+	now := utils.Clock()
+	if !q.Visible(now) {
+		return model.Question{}, errQuestionNotVisible
+	}
+	return q.Effective(now), nil
+}
+
+// SetValidity sets the window of time the question stored at key is
+// visible in.
+func (s *service) SetValidity(ctx context.Context, key model.Key, from, until time.Time) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.SetValidity")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.SetValidity(from, until)
+	})
+}
+
+// ScheduleValue replaces the value of the question stored at key with value
+// at time at.
+func (s *service) ScheduleValue(ctx context.Context, key model.Key, value model.Value, at time.Time) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.ScheduleValue")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.ScheduleValue(value, at)
+	})
+}
+
+func (s *service) CancelSchedule(ctx context.Context, key model.Key) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.CancelSchedule")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.CancelSchedule()
+	})
+}
+
+// RunSchedule appends the events of the scheduled values and validity
+// windows passed by now, and returns the number of questions changed. Each
+// question is changed in its own transaction, so a question that can't be
+// changed doesn't hold back the others.
+func (s *service) RunSchedule(ctx context.Context) (n int, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RunSchedule")
+	now := utils.Clock()
+	var keys []model.Key
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		b := tx.Bucket(scheduleBucket)
+		if b == nil {
+			return fmt.Errorf("bucket not found")
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			keys = append(keys, model.Key(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	var failed []string
+	for _, key := range keys {
+		var changed bool
+		err := s.updateTx(ctx, func(tx *tenantTx) error {
+			q, err := get(tx, key)
+			if err != nil {
+				return err
+			}
+			events := len(q.History)
+			q.Tick(now)
+			if len(q.History) == events {
+				return nil
+			}
+			changed = true
+			return s.put(ctx, tx, &q, q.History[events:])
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		if changed {
+			n++
+		}
+	}
+	if len(failed) > 0 {
+		return n, fmt.Errorf("run the schedule of %d questions: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return n, nil
+}
@@ -0,0 +1,231 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestServiceSchedule(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	start := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, key := range []model.Key{"holiday-hours", "opening-hours"} {
+		if _, err := s.New(ctx, key, "9 to 5"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.SetValidity(ctx, "holiday-hours", start.AddDate(0, 0, 20), start.AddDate(0, 1, 0)); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.SetValidity(ctx, "holiday-hours", start.AddDate(0, 1, 0), start); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if err := s.ScheduleValue(ctx, "opening-hours", "10 to 4", start.AddDate(0, 0, 24)); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name        string
+		days        int
+		wantChanged int
+		want        map[model.Key]model.Value
+	}{
+		{
+			name: "before the window",
+			days: 1,
+			want: map[model.Key]model.Value{"opening-hours": "9 to 5"},
+		},
+		{
+			name:        "inside the window",
+			days:        21,
+			wantChanged: 1,
+			want:        map[model.Key]model.Value{"holiday-hours": "9 to 5", "opening-hours": "9 to 5"},
+		},
+		{
+			name:        "after the scheduled value",
+			days:        25,
+			wantChanged: 1,
+			want:        map[model.Key]model.Value{"holiday-hours": "9 to 5", "opening-hours": "10 to 4"},
+		},
+		{
+			name:        "after the window",
+			days:        40,
+			wantChanged: 1,
+			want:        map[model.Key]model.Value{"opening-hours": "10 to 4"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			now = start.AddDate(0, 0, tt.days)
+			list, err := s.List(ctx)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			got := map[model.Key]model.Value{}
+			for _, q := range list {
+				got[q.Key] = q.Value
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected list mismatch (-want +got):\n%s", diff)
+			}
+			for _, key := range []model.Key{"holiday-hours", "opening-hours"} {
+				q, err := s.Get(ctx, key)
+				if want, ok := tt.want[key]; ok {
+					if err != nil {
+						t.Fatalf("got = %v, want nil", err)
+					}
+					checkAsserts(t, q.Value, want)
+				} else if err == nil {
+					t.Fatalf("got = nil, want error")
+				}
+			}
+			changed, err := s.RunSchedule(ctx)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			checkAsserts(t, changed, tt.wantChanged)
+		})
+	}
+
+	history, err := s.History(ctx, "opening-hours")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, history[0].Event.String(), "scheduled_update")
+	if changed, err := s.RunSchedule(ctx); err != nil || changed != 0 {
+		t.Fatalf("got = %d, %v, want 0, nil", changed, err)
+	}
+}
+
+func TestServiceScheduleCycle(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	start := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, key := range []model.Key{"contact", "email", "phone"} {
+		if _, err := s.New(ctx, key, "Ask us"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+
+	// The values referencing their question are rejected before they are due.
+	if err := s.ScheduleValue(ctx, "contact", "See [[contact]]", start.AddDate(0, 0, 1)); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if _, err := s.ProposeDraft(ctx, "contact", "See [[contact]]"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+
+	// A cycle made after the value was scheduled only holds back its question.
+	if err := s.ScheduleValue(ctx, "contact", "See [[email]]", start.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.ScheduleValue(ctx, "phone", "555-0100", start.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Update(ctx, "email", "See [[contact]]"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	now = start.AddDate(0, 0, 2)
+	changed, err := s.RunSchedule(ctx)
+	if err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	checkAsserts(t, changed, 1)
+	q, err := s.Get(ctx, "phone")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, q.Value, model.Value("555-0100"))
+	history, err := s.History(ctx, "contact")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, history[0].Event.String(), "schedule")
+}
+
+func TestServiceScheduleVisibility(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	start := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(ctx, "holiday-hours", "Closed on holidays"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.SetValidity(ctx, "holiday-hours", start.AddDate(0, 0, 20), start.AddDate(0, 1, 0)); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	// Outside of its window, a question isn't found by Search, Ask or
+	// Transclude.
+	results, err := s.Search(ctx, "holidays", 10)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, len(results), 0)
+	matches, err := s.Ask(ctx, "holiday hours", 10)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, len(matches), 0)
+	v, err := s.Transclude(ctx, "See [[holiday-hours]]", false, nil, nil, language.Und)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, v, model.Value("See [[holiday-hours]]"))
+
+	now = start.AddDate(0, 0, 21)
+	results, err = s.Search(ctx, "holidays", 10)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, len(results), 1)
+	matches, err = s.Ask(ctx, "holiday hours", 10)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, len(matches), 1)
+	v, err = s.Transclude(ctx, "See [[holiday-hours]]", false, nil, nil, language.Und)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, v, model.Value("See Closed on holidays"))
+}
@@ -0,0 +1,249 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"math"
+	"sort"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/text"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// searchTermBucket holds a nested bucket per term, mapping the keys of
+	// the questions containing it to its frequency.
+	searchTermBucket = []byte("search_terms")
+	// searchDocBucket maps the keys of the questions indexed to their terms.
+	searchDocBucket = []byte("search_docs")
+	// searchStatBucket holds the number of questions indexed and the sum of
+	// their lengths.
+	searchStatBucket = []byte("search_stats")
+
+	statDocs   = []byte("docs")
+	statLength = []byte("length")
+)
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// snippetSize is the number of words of the snippets of search results.
+const snippetSize = 30
+
+// searchDoc is a question as seen by the index.
+type searchDoc struct {
+	Terms  map[string]int
+	Length int
+}
+
+// indexSearch adds q to the search index, replacing the previous terms of its key.
+func indexSearch(tx *tenantTx, q *model.Question) error {
+	if err := unindexSearch(tx, q.Key); err != nil {
+		return err
+	}
+	tokens := append(text.Tokenize(string(q.Key)), text.Tokenize(string(q.Value))...)
+	if len(tokens) == 0 {
+		return nil
+	}
+	doc := searchDoc{Terms: map[string]int{}, Length: len(tokens)}
+	for _, t := range tokens {
+		doc.Terms[t]++
+	}
+	tBucket := tx.Bucket(searchTermBucket)
+	dBucket := tx.Bucket(searchDocBucket)
+	if tBucket == nil || dBucket == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	for t, n := range doc.Terms {
+		b, err := tBucket.CreateBucketIfNotExists([]byte(t))
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(q.Key), itob(uint64(n))); err != nil {
+			return err
+		}
+	}
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(doc); err != nil {
+		return err
+	}
+	if err := dBucket.Put([]byte(q.Key), data.Bytes()); err != nil {
+		return err
+	}
+	return addStats(tx, 1, int64(doc.Length))
+}
+
+// unindexSearch removes key from the search index.
+func unindexSearch(tx *tenantTx, key model.Key) error {
+	tBucket := tx.Bucket(searchTermBucket)
+	dBucket := tx.Bucket(searchDocBucket)
+	if tBucket == nil || dBucket == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	doc, ok, err := getSearchDoc(dBucket, []byte(key))
+	if err != nil || !ok {
+		return err
+	}
+	for t := range doc.Terms {
+		b := tBucket.Bucket([]byte(t))
+		if b == nil {
+			continue
+		}
+		if err := b.Delete([]byte(key)); err != nil {
+			return err
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			if err := tBucket.DeleteBucket([]byte(t)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := dBucket.Delete([]byte(key)); err != nil {
+		return err
+	}
+	return addStats(tx, -1, -int64(doc.Length))
+}
+
+func getSearchDoc(b *bolt.Bucket, key []byte) (searchDoc, bool, error) {
+	var doc searchDoc
+	data := b.Get(key)
+	if data == nil {
+		return doc, false, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return doc, false, err
+	}
+	return doc, true, nil
+}
+
+func addStats(tx *tenantTx, docs, length int64) error {
+	b := tx.Bucket(searchStatBucket)
+	if b == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	for stat, delta := range map[string]int64{string(statDocs): docs, string(statLength): length} {
+		v := int64(getStat(b, []byte(stat))) + delta
+		if err := b.Put([]byte(stat), itob(uint64(v))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getStat(b *bolt.Bucket, stat []byte) uint64 {
+	v := b.Get(stat)
+	if len(v) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+// Search returns at most limit questions matching query, the most relevant
+// first, among the ones readable by the principal of ctx and visible now. Relevance is
+// scored with BM25 over the keys and values. A search without results is
+// recorded as a miss.
+func (s *service) Search(ctx context.Context, query string, limit int) (_ []model.SearchResult, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Search")
+	terms := unique(text.Tokenize(query))
+	var results []model.SearchResult
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		tBucket := tx.Bucket(searchTermBucket)
+		dBucket := tx.Bucket(searchDocBucket)
+		sBucket := tx.Bucket(searchStatBucket)
+		if tBucket == nil || dBucket == nil || sBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		n := float64(getStat(sBucket, statDocs))
+		if n == 0 {
+			return nil
+		}
+		avgLength := float64(getStat(sBucket, statLength)) / n
+
+		scores := map[string]float64{}
+		lengths := map[string]float64{}
+		for _, t := range terms {
+			b := tBucket.Bucket([]byte(t))
+			if b == nil {
+				continue
+			}
+			df := float64(b.Stats().KeyN)
+			idf := math.Log((n-df+0.5)/(df+0.5) + 1)
+			err := b.ForEach(func(k, v []byte) error {
+				key := string(k)
+				if _, ok := lengths[key]; !ok {
+					doc, _, err := getSearchDoc(dBucket, k)
+					if err != nil {
+						return err
+					}
+					lengths[key] = float64(doc.Length)
+				}
+				tf := float64(binary.BigEndian.Uint64(v))
+				norm := 1 - bm25B + bm25B*lengths[key]/avgLength
+				scores[key] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for key, score := range scores {
+			results = append(results, model.SearchResult{
+				Question: model.Question{Key: model.Key(key)},
+				Score:    score,
+			})
+		}
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].Score != results[j].Score {
+				return results[i].Score > results[j].Score
+			}
+			return results[i].Question.Key < results[j].Question.Key
+		})
+		var l []model.SearchResult
+		for _, r := range results {
+			if limit > 0 && len(l) == limit {
+				break
+			}
+			q, err := load(tx, r.Question.Key)
+			if err != nil {
+				return err
+			}
+			if !readable(ctx, q) {
+				continue
+			}
+			if q, err = visible(q); err != nil {
+				continue
+			}
+			r.Question = q
+			r.Snippet = text.Highlight(string(q.Value), terms, snippetSize)
+			l = append(l, r)
+		}
+		results = l
+		return nil
+	})
+	if err == nil && len(results) == 0 {
+		err = s.recordMiss(ctx, searchMissKey(query), query)
+	}
+	return results, err
+}
+
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var l []string
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			l = append(l, t)
+		}
+	}
+	return l
+}
@@ -0,0 +1,108 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func TestServiceSearch(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, in := range []struct {
+		key   model.Key
+		value model.Value
+	}{
+		{"refund-policy", "Refunds are accepted within 30 days."},
+		{"shipping", "We ship in 2 days. Refunds of shipping costs are not possible."},
+		{"opening-hours", "Open from 9 to 5."},
+		{"old", "Refunds were never accepted."},
+	} {
+		if _, err := s.New(ctx, in.key, in.value); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.Update(ctx, "opening-hours", "Open from 9 to 5, refunds at the desk."); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Delete(ctx, "old"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	type result struct {
+		Key     model.Key
+		Snippet string
+	}
+	var testCases = []struct {
+		name  string
+		query string
+		limit int
+		want  []result
+	}{
+		{
+			name:  "ranked by relevance",
+			query: "refunds policy",
+			want: []result{
+				{"refund-policy", "<mark>Refunds</mark> are accepted within 30 days"},
+				{"opening-hours", "Open from 9 to 5, <mark>refunds</mark> at the desk"},
+				{"shipping", "We ship in 2 days. <mark>Refunds</mark> of shipping costs are not possible"},
+			},
+		},
+		{
+			name:  "limit",
+			query: "shipping",
+			limit: 1,
+			want: []result{
+				{"shipping", "We ship in 2 days. Refunds of <mark>shipping</mark> costs are not possible"},
+			},
+		},
+		{
+			name:  "no match",
+			query: "never",
+		},
+	}
+
+	run := func(t *testing.T) {
+		for _, tt := range testCases {
+			t.Run(tt.name, func(t *testing.T) {
+				results, err := s.Search(ctx, tt.query, tt.limit)
+				if err != nil {
+					t.Fatalf("got = %v, want nil", err)
+				}
+				var got []result
+				for _, r := range results {
+					got = append(got, result{r.Question.Key, r.Snippet})
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("unexpected results mismatch (-want +got):\n%s", diff)
+				}
+			})
+		}
+	}
+	t.Run("index", run)
+
+	// Drop the index to check that it is rebuilt from the questions.
+	err = db.Update(func(tx *bbolt.Tx) error {
+		return tx.DeleteBucket(searchTermBucket)
+	})
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Reindex(ctx); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	t.Run("reindex", run)
+}
@@ -1,13 +1,14 @@
 package bolt
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"answer.io/pkg/derrors"
 	"answer.io/pkg/model"
 	"answer.io/pkg/utils"
-	"bytes"
-	"encoding/gob"
-	"errors"
-	"fmt"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -15,31 +16,84 @@ import (
 var (
 	questionBucket        = []byte("questions")
 	deletedQuestionBucket = []byte("deleted_questions")
+	eventBucket           = []byte("events")
+	snapshotBucket        = []byte("snapshots")
+	redirectBucket        = []byte("redirects")
+	idBucket              = []byte("question_ids")
+	missBucket            = []byte("misses")
+	aliasBucket           = []byte("aliases")
 )
 
+// errQuestionNotFound is returned when there is no question at a key.
+var errQuestionNotFound = fmt.Errorf("question %w", derrors.NotFound)
+
+// defaultSnapshotInterval is the number of events between two snapshots of
+// a question.
+const defaultSnapshotInterval = 100
+
 type service struct {
-	db *bolt.DB
+	db               *bolt.DB
+	snapshotInterval uint64
+	askThreshold     float64
+	maxMisses        int
+}
+
+// Option configures the service.
+type Option func(*service)
+
+// WithSnapshotInterval sets the number of events appended to a question
+// between two snapshots of it. Zero disables the snapshots.
+func WithSnapshotInterval(n uint64) Option {
+	return func(s *service) {
+		s.snapshotInterval = n
+	}
 }
 
-func NewService(db *bolt.DB) (*service, error) {
+func NewService(db *bolt.DB, opts ...Option) (*service, error) {
+	s := &service{
+		db:               db,
+		snapshotInterval: defaultSnapshotInterval,
+		askThreshold:     defaultAskThreshold,
+		maxMisses:        defaultMaxMisses,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
 	err := db.Update(func(tx *bolt.Tx) error {
-		if _, err := tx.CreateBucketIfNotExists(deletedQuestionBucket); err != nil {
+		if err := createBuckets(tx); err != nil {
 			return err
 		}
-		_, err := tx.CreateBucketIfNotExists(questionBucket)
+		for _, name := range [][]byte{apiKeyBucket, userBucket, resetTokenBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		_, err := tx.CreateBucketIfNotExists(tenantBucket)
 		return err
 	})
-	return &service{db: db}, err
+	return s, err
 }
 
-func (s *service) New(key model.Key, value model.Value) (_ *model.Question, err error) {
+func (s *service) New(ctx context.Context, key model.Key, value model.Value) (_ *model.Question, err error) {
 	defer derrors.WrapStack(&err, "bolt.service.New")
+	return s.create(ctx, model.New(utils.NextID(), key, value))
+}
+
+// NewTemplate is like New but the value of the question is a template,
+// rejected when it is not valid.
+func (s *service) NewTemplate(ctx context.Context, key model.Key, value model.Value) (_ *model.Question, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.NewTemplate")
 	q := model.New(utils.NextID(), key, value)
-	var data bytes.Buffer
-	if err := gob.NewEncoder(&data).Encode(q); err != nil {
+	if err := q.SetTemplate(true); err != nil {
 		return nil, err
 	}
-	err = s.db.Update(func(tx *bolt.Tx) error {
+	return s.create(ctx, q)
+}
+
+// create stores the new question q.
+func (s *service) create(ctx context.Context, q *model.Question) (*model.Question, error) {
+	key := q.Key
+	err := s.updateTx(ctx, func(tx *tenantTx) error {
 		qBucket := tx.Bucket(questionBucket)
 		dBucket := tx.Bucket(deletedQuestionBucket)
 		if qBucket == nil || dBucket == nil {
@@ -49,7 +103,35 @@ func (s *service) New(key model.Key, value model.Value) (_ *model.Question, err
 		if data := qBucket.Get([]byte(key)); len(data) > 0 && len(d) == 0 {
 			return errors.New("key already exist")
 		}
-		if err := qBucket.Put([]byte(key), data.Bytes()); err != nil {
+		if to, ok := aliasOf(tx, key); ok {
+			return fmt.Errorf("key %q is an alias of %q: %w", key, to, derrors.Conflict)
+		}
+		events := q.Events()
+		// A key created again after a delete continues its log, keeping
+		// the history of the deleted question.
+		if len(d) > 0 {
+			old, err := load(tx, key)
+			if err != nil {
+				return err
+			}
+			if err := authorize(ctx, old, model.PermissionWrite); err != nil {
+				return err
+			}
+			n := len(old.History)
+			if err := old.Recreate(q); err != nil {
+				return err
+			}
+			q, events = &old, old.History[n:]
+		}
+		if err := s.put(ctx, tx, q, events); err != nil {
+			return err
+		}
+		// The key no longer points to a renamed question.
+		if err := tx.Bucket(redirectBucket).Delete([]byte(key)); err != nil {
+			return err
+		}
+		// Nor is missing anymore.
+		if err := tx.Bucket(missBucket).Delete([]byte(key)); err != nil {
 			return err
 		}
 		return dBucket.Delete([]byte(key))
@@ -57,102 +139,323 @@ func (s *service) New(key model.Key, value model.Value) (_ *model.Question, err
 	if err != nil {
 		return nil, err
 	}
+	return q, nil
+}
+
+// Get returns the question stored at key as it is now, unless it is outside
+// of its validity window or the principal of ctx isn't allowed to read it.
+// A key without question is recorded as a miss.
+func (s *service) Get(ctx context.Context, key model.Key) (model.Question, error) {
+	var q model.Question
+	err := s.viewTx(ctx, func(tx *tenantTx) error {
+		var err error
+		if q, err = get(tx, key); err != nil {
+			return err
+		}
+		if err := authorize(ctx, q, model.PermissionRead); err != nil {
+			return err
+		}
+		q, err = visible(q)
+		return err
+	})
+	if errors.Is(err, errQuestionNotFound) {
+		if merr := s.recordMiss(ctx, key, model.SourceFromContext(ctx)); merr != nil {
+			return q, merr
+		}
+	}
 	return q, err
 }
 
-func (s *service) Get(key model.Key) (model.Question, error) {
+// get returns the question stored at key, or the one key is an alias of.
+// The History of the question only holds the events appended after the
+// snapshot it was restored from.
+func get(tx *tenantTx, key model.Key) (model.Question, error) {
 	var q model.Question
-	err := s.db.View(func(tx *bolt.Tx) error {
-		qBucket := tx.Bucket(questionBucket)
-		dBucket := tx.Bucket(deletedQuestionBucket)
-		if qBucket == nil || deletedQuestionBucket == nil {
+	qBucket := tx.Bucket(questionBucket)
+	dBucket := tx.Bucket(deletedQuestionBucket)
+	if qBucket == nil || dBucket == nil {
+		return q, errors.New("bucket doesn't exist")
+	}
+	if !live(tx, key) {
+		to, ok := aliasOf(tx, key)
+		switch {
+		case ok:
+			key = to
+		case len(dBucket.Get([]byte(key))) > 0:
+			return q, errors.New("question deleted")
+		default:
+			return q, notFound(tx, key)
+		}
+	}
+	q, err := load(tx, key)
+	if err != nil {
+		return q, fmt.Errorf("service.Get: %w", err)
+	}
+	return q, nil
+}
+
+// GetByID returns the question identified by id, wherever its key is.
+func (s *service) GetByID(ctx context.Context, id utils.ID) (_ model.Question, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.GetByID")
+	var q model.Question
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		iBucket := tx.Bucket(idBucket)
+		if iBucket == nil {
 			return errors.New("bucket doesn't exist")
 		}
-		if data := dBucket.Get([]byte(key)); len(data) > 0 {
-			return errors.New("question deleted")
+		key := iBucket.Get(id)
+		if len(key) == 0 {
+			return errQuestionNotFound
 		}
-		data := qBucket.Get([]byte(key))
-		if len(data) == 0 {
-			return fmt.Errorf("question not found")
+		var err error
+		if q, err = get(tx, model.Key(key)); err != nil {
+			return err
 		}
-		if err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&q); err != nil {
-			return fmt.Errorf("service.Get: %w", err)
+		if err := authorize(ctx, q, model.PermissionRead); err != nil {
+			return err
 		}
-		return nil
+		q, err = visible(q)
+		return err
 	})
 	return q, err
 }
 
-func (s *service) Update(key model.Key, value model.Value) error {
-	q, err := s.Get(key)
-	if err != nil {
+// GetAt returns the question stored at key as it was at version. It works
+// for deleted questions too. The current ACL of the question applies to its
+// past versions.
+func (s *service) GetAt(ctx context.Context, key model.Key, version int) (_ model.Question, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.GetAt")
+	var q model.Question
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		if err := authorizeKey(ctx, tx, key, model.PermissionRead); err != nil {
+			return err
+		}
+		var err error
+		q, err = getAt(tx, key, version)
 		return err
+	})
+	return q, err
+}
+
+func getAt(tx *tenantTx, key model.Key, version int) (model.Question, error) {
+	seq, err := searchEvents(tx, key, func(env model.Envelope) bool {
+		return env.Version <= version
+	})
+	if err != nil {
+		return model.Question{}, err
 	}
-	if err := q.Update(value); err != nil {
-		return err
+	if seq == 0 {
+		return model.Question{}, fmt.Errorf("version %d not found", version)
 	}
-	var data bytes.Buffer
-	if err := gob.NewEncoder(&data).Encode(q); err != nil {
-		return err
+	q, err := loadAt(tx, key, seq)
+	if err != nil {
+		return q, err
 	}
-	return s.db.Update(func(tx *bolt.Tx) error {
-		qBucket := tx.Bucket(questionBucket)
-		if qBucket == nil {
-			return fmt.Errorf("bucket not found")
+	if q.Version != version {
+		return q, fmt.Errorf("version %d not found", version)
+	}
+	return q, nil
+}
+
+// GetAsOf returns the question stored at key as it was at time t. It works
+// for deleted questions too. The current ACL of the question applies to its
+// past versions.
+func (s *service) GetAsOf(ctx context.Context, key model.Key, t time.Time) (_ model.Question, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.GetAsOf")
+	var q model.Question
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		if err := authorizeKey(ctx, tx, key, model.PermissionRead); err != nil {
+			return err
+		}
+		seq, err := searchEvents(tx, key, func(env model.Envelope) bool {
+			return !env.Timestamp.After(t)
+		})
+		if err != nil {
+			return err
+		}
+		if seq == 0 {
+			return fmt.Errorf("question not found at %s", t.Format(time.RFC3339))
+		}
+		q, err = loadAt(tx, key, seq)
+		return err
+	})
+	return q, err
+}
+
+func (s *service) Update(ctx context.Context, key model.Key, value model.Value) error {
+	return s.update(ctx, key, value, nil)
+}
+
+// UpdateIfVersion is like Update but fails with derrors.Conflict when the
+// question is at none of the expected versions.
+func (s *service) UpdateIfVersion(ctx context.Context, key model.Key, value model.Value, expected ...int) error {
+	return s.update(ctx, key, value, expected)
+}
+
+func (s *service) update(ctx context.Context, key model.Key, value model.Value, expected []int) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Update")
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		q, err := get(tx, key)
+		if err != nil {
+			return err
 		}
-		return qBucket.Put([]byte(key), data.Bytes())
+		if err := authorize(ctx, q, model.PermissionWrite); err != nil {
+			return err
+		}
+		if err := checkVersion(q, expected); err != nil {
+			return err
+		}
+		n := len(q.History)
+		if err := q.Update(value); err != nil {
+			return err
+		}
+		return s.put(ctx, tx, &q, q.History[n:])
 	})
 }
 
-func (s *service) Delete(key model.Key) error {
-	q, err := s.Get(key)
-	if err != nil {
-		return err
-	}
-	return s.db.Update(func(tx *bolt.Tx) error {
+func (s *service) Delete(ctx context.Context, key model.Key) error {
+	return s.delete(ctx, key, nil)
+}
+
+// DeleteIfVersion is like Delete but fails with derrors.Conflict when the
+// question is at none of the expected versions.
+func (s *service) DeleteIfVersion(ctx context.Context, key model.Key, expected ...int) error {
+	return s.delete(ctx, key, expected)
+}
+
+func (s *service) delete(ctx context.Context, key model.Key, expected []int) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Delete")
+	return s.updateTx(ctx, func(tx *tenantTx) error {
+		q, err := get(tx, key)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, q, model.PermissionWrite); err != nil {
+			return err
+		}
+		if err := checkVersion(q, expected); err != nil {
+			return err
+		}
+		n := len(q.History)
+		if err := q.Delete(); err != nil {
+			return err
+		}
+		if err := s.put(ctx, tx, &q, q.History[n:]); err != nil {
+			return err
+		}
 		dBucket := tx.Bucket(deletedQuestionBucket)
-		if dBucket == nil {
+		iBucket := tx.Bucket(idBucket)
+		if dBucket == nil || iBucket == nil {
 			return fmt.Errorf("bucket not found")
 		}
+		if err := iBucket.Delete(q.Id); err != nil {
+			return err
+		}
 		return dBucket.Put([]byte(q.Key), q.Id)
 	})
 }
 
-func (s *service) List() ([]model.Question, error) {
-	var l []model.Question
-	return l, s.db.View(func(tx *bolt.Tx) error {
-		qBucket := tx.Bucket(questionBucket)
+// Restore brings back the question deleted at key, keeping its history.
+func (s *service) Restore(ctx context.Context, key model.Key) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Restore")
+	return s.updateTx(ctx, func(tx *tenantTx) error {
 		dBucket := tx.Bucket(deletedQuestionBucket)
-		if qBucket == nil || dBucket == nil {
+		if dBucket == nil {
 			return fmt.Errorf("bucket not found")
 		}
-		cursor := qBucket.Cursor()
-		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
-			var q model.Question
-			if data := dBucket.Get(k); len(data) > 0 {
-				continue
-			}
-			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(&q); err != nil {
-				return err
-			}
-			l = append(l, q)
+		if data := dBucket.Get([]byte(key)); len(data) == 0 {
+			return errors.New("question not deleted")
 		}
-		return nil
+		q, err := load(tx, key)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, q, model.PermissionWrite); err != nil {
+			return err
+		}
+		n := len(q.History)
+		if err := q.Restore(); err != nil {
+			return err
+		}
+		if err := s.put(ctx, tx, &q, q.History[n:]); err != nil {
+			return err
+		}
+		return dBucket.Delete([]byte(key))
 	})
 }
 
-func (s *service) History(key model.Key) (_ []model.Event, err error) {
+// checkVersion returns derrors.Conflict when q is at none of the expected
+// versions. Any version is expected when there are none.
+func checkVersion(q model.Question, expected []int) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	for _, v := range expected {
+		if q.Version == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("question at version %d, expected %v: %w", q.Version, expected, derrors.Conflict)
+}
+
+// put appends to the log of q the events raised since it was read, and
+// takes a snapshot of q when enough events were appended since the last one.
+// It keeps the ID of q pointing to its key and the indexes of its text up to
+// date, and rejects q when it references itself or when its value changes
+// outside of a draft the principal of ctx isn't allowed to skip.
+func (s *service) put(ctx context.Context, tx *tenantTx, q *model.Question, events []model.Event) error {
+	if err := checkDraftRequired(ctx, q, events); err != nil {
+		return err
+	}
+	qBucket := tx.Bucket(questionBucket)
+	iBucket := tx.Bucket(idBucket)
+	if qBucket == nil || iBucket == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	if err := qBucket.Put([]byte(q.Key), q.Id); err != nil {
+		return err
+	}
+	if err := iBucket.Put(q.Id, []byte(q.Key)); err != nil {
+		return err
+	}
+	seq, err := appendEvents(ctx, tx, q.Key, q.Version, events)
+	if err != nil {
+		return err
+	}
+	if err := putAliases(tx, q, events); err != nil {
+		return err
+	}
+	if q.Deleted {
+		err = unindex(tx, q.Key)
+	} else if err = checkCycle(tx, q, events); err == nil {
+		err = index(tx, q)
+	}
+	if err != nil {
+		return err
+	}
+	return s.snapshot(tx, q, seq)
+}
+
+func (s *service) List(ctx context.Context) ([]model.Question, error) {
+	l, _, err := s.ListPage(ctx, model.ListOptions{})
+	return l, err
+}
+
+func (s *service) History(ctx context.Context, key model.Key) (_ []model.Envelope, err error) {
 	defer derrors.WrapStack(&err, "bolt.service.History")
-	var list []model.Event
-	err = s.db.View(func(tx *bolt.Tx) error {
-		qhBucket := tx.Bucket([]byte(key))
-		if qhBucket == nil {
-			return fmt.Errorf("bucket doesn't exist")
+	var list []model.Envelope
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		if err := authorizeKey(ctx, tx, key, model.PermissionRead); err != nil {
+			return err
+		}
+		qhBucket, err := events(tx, key)
+		if err != nil {
+			return err
 		}
 		c := qhBucket.Cursor()
 		for k, v := c.Last(); k != nil; k, v = c.Prev() {
-			var e model.Event
-			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(e); err != nil {
+			e, err := decodeEnvelope(v)
+			if err != nil {
 				return err
 			}
 			list = append(list, e)
@@ -1,13 +1,17 @@
 package bolt
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
+	"answer.io/pkg/derrors"
 	"answer.io/pkg/model"
 	"answer.io/pkg/utils"
 
@@ -50,6 +54,7 @@ func mustOpenDB(t testing.TB) (*bbolt.DB, func(t testing.TB)) {
 func TestServiceNew(t *testing.T) {
 	db, clean := mustOpenDB(t)
 	defer clean(t)
+	ctx := context.Background()
 	utils.Generator = func() string {
 		return "test_id_generator"
 	}
@@ -102,7 +107,7 @@ func TestServiceNew(t *testing.T) {
 			if err != nil {
 				t.Fatalf("got = %v, want nil", err)
 			}
-			got, err := s.New(tt.in.Key, tt.in.value)
+			got, err := s.New(ctx, tt.in.Key, tt.in.value)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("got = %v, want nil", err)
 			}
@@ -116,6 +121,7 @@ func TestServiceNew(t *testing.T) {
 func TestServiceGet(t *testing.T) {
 	db, clean := mustOpenDB(t)
 	defer clean(t)
+	ctx := context.Background()
 	utils.Generator = func() string {
 		return "test_id_generator"
 	}
@@ -156,9 +162,9 @@ func TestServiceGet(t *testing.T) {
 				t.Fatalf("got = %v, want nil", err)
 			} else {
 				if tt.want.Value != "" {
-					s.New(tt.in.key, tt.want.Value)
+					s.New(ctx, tt.in.key, tt.want.Value)
 				}
-				got, err := s.Get(tt.in.key)
+				got, err := s.Get(ctx, tt.in.key)
 				if (err != nil) != tt.wantErr {
 					t.Fatalf("got = %v, want nil", err)
 				}
@@ -173,6 +179,7 @@ func TestServiceGet(t *testing.T) {
 func TestServiceUpdate(t *testing.T) {
 	db, clean := mustOpenDB(t)
 	defer clean(t)
+	ctx := context.Background()
 	utils.Generator = func() string {
 		return "test_id_generator"
 	}
@@ -318,15 +325,15 @@ func TestServiceUpdate(t *testing.T) {
 			if err != nil {
 				t.Fatalf("got = %v, want nil", err)
 			}
-			if  _, err := s.New(tt.in.newIn.key, tt.in.newIn.value); err != nil {
+			if  _, err := s.New(ctx, tt.in.newIn.key, tt.in.newIn.value); err != nil {
 				t.Fatalf("got = %v, want nil", err)
 			}
-			err = s.Update(tt.in.updateIn.key, tt.in.updateIn.value)
+			err = s.Update(ctx, tt.in.updateIn.key, tt.in.updateIn.value)
 			if (err != nil) != tt.wantErr {
  				t.Fatalf("got = %v, want nil", err)
 			}
 			if err == nil {
-				got, err := s.Get(tt.in.newIn.key)
+				got, err := s.Get(ctx, tt.in.newIn.key)
 				if (err != nil) != tt.wantErr {
 					t.Fatalf("got = %v, want nil", err)
 				}
@@ -341,6 +348,7 @@ func TestServiceUpdate(t *testing.T) {
 func TestServiceDelete(t *testing.T) {
 	db, clean := mustOpenDB(t)
 	defer clean(t)
+	ctx := context.Background()
 	utils.Generator = func() string {
 		return "test_id_generator"
 	}
@@ -382,11 +390,11 @@ func TestServiceDelete(t *testing.T) {
 				t.Fatalf("got = %v, want nil", err)
 			}
 			if tt.in.value != "" {
-				if _, err := s.New(tt.in.key, tt.in.value); err != nil {
+				if _, err := s.New(ctx, tt.in.key, tt.in.value); err != nil {
 					t.Fatalf("got = %v, want nil", err)
 				}
 			}
-			if err := s.Delete(tt.in.key); (err != nil) != tt.wantErr {
+			if err := s.Delete(ctx, tt.in.key); (err != nil) != tt.wantErr {
 				t.Fatalf("got = %v, want nil", err)
 			}
 		})
@@ -396,6 +404,7 @@ func TestServiceDelete(t *testing.T) {
 func TestServiceList(t *testing.T) {
 	db, clean := mustOpenDB(t)
 	defer clean(t)
+	ctx := context.Background()
 	utils.Generator = func() string {
 		return "test_id_generator"
 	}
@@ -456,11 +465,389 @@ func TestServiceList(t *testing.T) {
 				t.Fatalf("got = %v, want nil", err)
 			}
 			for _, in := range tt.in {
-				if _, err := s.New(in.key, in.value); err != nil {
+				if _, err := s.New(ctx, in.key, in.value); err != nil {
 					t.Fatalf("got = %v, want nil", err)
 				}
 			}
-			got, err := s.List()
+			got, err := s.List(ctx)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected question mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServiceHistory(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	ctx = model.NewContextWithActor(ctx, "john")
+	var testCases = []struct {
+		name    string
+		key     model.Key
+		run     func(s *service) error
+		want    []model.Envelope
+		wantErr bool
+	}{
+		{
+			name: "history newest first",
+			key:  model.Key("name"),
+			run: func(s *service) error {
+				if _, err := s.New(ctx, "name", "John"); err != nil {
+					return err
+				}
+				if err := s.Update(ctx, "name", "John Doe"); err != nil {
+					return err
+				}
+				return s.Delete(ctx, "name")
+			},
+			want: []model.Envelope{
+				{
+					ID:        utils.NextID(),
+					Sequence:  3,
+					Version:   2,
+					Timestamp: now,
+					Actor:     "john",
+					Event:     model.QuestionDelete{Key: model.Key("name")},
+				},
+				{
+					ID:        utils.NextID(),
+					Sequence:  2,
+					Version:   1,
+					Timestamp: now,
+					Actor:     "john",
+					Event: model.QuestionUpdate{
+						Key:      model.Key("name"),
+						NewValue: model.Value("John Doe"),
+					},
+				},
+				{
+					ID:        utils.NextID(),
+					Sequence:  1,
+					Timestamp: now,
+					Actor:     "john",
+					Event: model.QuestionAdded{
+						ID:    utils.NextID(),
+						Key:   model.Key("name"),
+						Value: model.Value("John"),
+					},
+				},
+			},
+		},
+		{
+			name: "history continues when the key is created again",
+			key:  model.Key("name"),
+			run: func(s *service) error {
+				_, err := s.New(ctx, "name", "Jane")
+				return err
+			},
+			want: []model.Envelope{
+				{
+					ID:        utils.NextID(),
+					Sequence:  4,
+					Version:   3,
+					Timestamp: now,
+					Actor:     "john",
+					Event: model.QuestionAdded{
+						ID:    utils.NextID(),
+						Key:   model.Key("name"),
+						Value: model.Value("Jane"),
+					},
+				},
+				{
+					ID:        utils.NextID(),
+					Sequence:  3,
+					Version:   2,
+					Timestamp: now,
+					Actor:     "john",
+					Event:     model.QuestionDelete{Key: model.Key("name")},
+				},
+				{
+					ID:        utils.NextID(),
+					Sequence:  2,
+					Version:   1,
+					Timestamp: now,
+					Actor:     "john",
+					Event: model.QuestionUpdate{
+						Key:      model.Key("name"),
+						NewValue: model.Value("John Doe"),
+					},
+				},
+				{
+					ID:        utils.NextID(),
+					Sequence:  1,
+					Timestamp: now,
+					Actor:     "john",
+					Event: model.QuestionAdded{
+						ID:    utils.NextID(),
+						Key:   model.Key("name"),
+						Value: model.Value("John"),
+					},
+				},
+			},
+		},
+		{
+			name:    "history of unknown key",
+			key:     model.Key("not_found_key"),
+			run:     func(s *service) error { return nil },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewService(db)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if err := tt.run(s); err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			got, err := s.History(ctx, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected history mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServiceIfVersion(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(ctx, "name", "John"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	var testCases = []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "update at expected version",
+			run: func() error {
+				return s.UpdateIfVersion(ctx, "name", "John Doe", 0)
+			},
+		},
+		{
+			name: "update at stale version",
+			run: func() error {
+				return s.UpdateIfVersion(ctx, "name", "Jane Doe", 0)
+			},
+			wantErr: derrors.Conflict,
+		},
+		{
+			name: "delete at stale version",
+			run: func() error {
+				return s.DeleteIfVersion(ctx, "name", 0)
+			},
+			wantErr: derrors.Conflict,
+		},
+		{
+			name: "delete at one of the expected versions",
+			run: func() error {
+				return s.DeleteIfVersion(ctx, "name", 0, 1)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceGetAt(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+
+	s, err := NewService(db, WithSnapshotInterval(2))
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(ctx, "name", "John"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, v := range []model.Value{"John Doe", "Jane", "Jane Doe"} {
+		now = now.Add(time.Hour)
+		if err := s.Update(ctx, "name", v); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	now = now.Add(time.Hour)
+	if err := s.Delete(ctx, "name"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name      string
+		get       func() (model.Question, error)
+		wantValue model.Value
+		wantVer   int
+		wantErr   bool
+	}{
+		{
+			name:      "first version",
+			get:       func() (model.Question, error) { return s.GetAt(ctx, "name", 0) },
+			wantValue: "John",
+		},
+		{
+			name:      "version restored from a snapshot",
+			get:       func() (model.Question, error) { return s.GetAt(ctx, "name", 3) },
+			wantValue: "Jane Doe",
+			wantVer:   3,
+		},
+		{
+			name:    "unknown version",
+			get:     func() (model.Question, error) { return s.GetAt(ctx, "name", 10) },
+			wantErr: true,
+		},
+		{
+			name:      "as of a time between two changes",
+			get:       func() (model.Question, error) { return s.GetAsOf(ctx, "name", start.Add(90*time.Minute)) },
+			wantValue: "John Doe",
+			wantVer:   1,
+		},
+		{
+			name:    "as of a time before the question existed",
+			get:     func() (model.Question, error) { return s.GetAsOf(ctx, "name", start.Add(-time.Hour)) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if err != nil {
+				return
+			}
+			checkAsserts(t, got.Value, tt.wantValue)
+			checkAsserts(t, got.Version, tt.wantVer)
+			checkAsserts(t, got.Deleted, false)
+		})
+	}
+
+	// Creating the key again keeps the versions of the deleted question.
+	now = now.Add(time.Hour)
+	if _, err := s.New(ctx, "name", "Alice"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	got, err := s.GetAt(ctx, "name", 2)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, got.Value, model.Value("Jane"))
+	got, err = s.Get(ctx, "name")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, got.Value, model.Value("Alice"))
+	checkAsserts(t, got.Version, 5)
+}
+
+func TestServiceRestore(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	var testCases = []struct {
+		name    string
+		key     model.Key
+		run     func(s *service) error
+		want    model.Question
+		wantErr bool
+	}{
+		{
+			name: "restore deleted question",
+			key:  model.Key("name"),
+			run: func(s *service) error {
+				if _, err := s.New(ctx, "name", "John"); err != nil {
+					return err
+				}
+				return s.Delete(ctx, "name")
+			},
+			want: model.Question{
+				Id:    utils.NextID(),
+				Key:   model.Key("name"),
+				Value: model.Value("John"),
+				History: []model.Event{
+					model.QuestionAdded{
+						ID:    utils.NextID(),
+						Key:   model.Key("name"),
+						Value: model.Value("John"),
+					},
+					model.QuestionDelete{Key: model.Key("name")},
+					model.QuestionRestored{Key: model.Key("name")},
+				},
+				Version: 2,
+			},
+		},
+		{
+			name:    "restore question not deleted",
+			key:     model.Key("name"),
+			run:     func(s *service) error { return nil },
+			wantErr: true,
+		},
+		{
+			name:    "restore unknown question",
+			key:     model.Key("not_found_key"),
+			run:     func(s *service) error { return nil },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewService(db)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if err := tt.run(s); err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			err = s.Restore(ctx, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if err != nil {
+				return
+			}
+			got, err := s.Get(ctx, tt.key)
 			if err != nil {
 				t.Fatalf("got = %v, want nil", err)
 			}
@@ -0,0 +1,70 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+
+	"answer.io/pkg/model"
+)
+
+// snapshot stores the state of q after the event at seq when at least
+// snapshotInterval events were appended since the last snapshot of q.
+func (s *service) snapshot(tx *tenantTx, q *model.Question, seq uint64) error {
+	if s.snapshotInterval == 0 {
+		return nil
+	}
+	sBucket := tx.Bucket(snapshotBucket)
+	if sBucket == nil {
+		return errors.New("bucket doesn't exist")
+	}
+	b, err := sBucket.CreateBucketIfNotExists([]byte(q.Key))
+	if err != nil {
+		return err
+	}
+	var last uint64
+	if k, _ := b.Cursor().Last(); k != nil {
+		last = binary.BigEndian.Uint64(k)
+	}
+	if seq-last < s.snapshotInterval {
+		return nil
+	}
+	snap := *q
+	snap.History = nil
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(snap); err != nil {
+		return err
+	}
+	return b.Put(itob(seq), data.Bytes())
+}
+
+// snapshotAt returns the latest snapshot of key taken at or before the event
+// at seq, and the sequence of the last event applied to it. It returns a zero
+// question and sequence when there is no such snapshot.
+func snapshotAt(tx *tenantTx, key model.Key, seq uint64) (model.Question, uint64, error) {
+	var q model.Question
+	sBucket := tx.Bucket(snapshotBucket)
+	if sBucket == nil {
+		return q, 0, errors.New("bucket doesn't exist")
+	}
+	b := sBucket.Bucket([]byte(key))
+	if b == nil {
+		return q, 0, nil
+	}
+	c := b.Cursor()
+	k, v := c.Seek(itob(seq))
+	switch {
+	case k == nil:
+		k, v = c.Last()
+	case binary.BigEndian.Uint64(k) > seq:
+		k, v = c.Prev()
+	}
+	if k == nil {
+		return q, 0, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&q); err != nil {
+		return q, 0, err
+	}
+	return q, binary.BigEndian.Uint64(k), nil
+}
@@ -0,0 +1,137 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceSnapshot(t *testing.T) {
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	ctx := context.Background()
+	var testCases = []struct {
+		name     string
+		interval uint64
+		updates  int
+		want     model.Question
+	}{
+		{
+			name:     "restore from snapshot and later events",
+			interval: 2,
+			updates:  4,
+			want: model.Question{
+				Id:      utils.NextID(),
+				Key:     "name",
+				Value:   "value 4",
+				Version: 4,
+				History: []model.Event{
+					model.QuestionUpdate{Key: "name", NewValue: "value 4"},
+				},
+			},
+		},
+		{
+			name:     "restore from snapshot only",
+			interval: 2,
+			updates:  3,
+			want: model.Question{
+				Id:      utils.NextID(),
+				Key:     "name",
+				Value:   "value 3",
+				Version: 3,
+			},
+		},
+		{
+			name:    "restore without snapshots",
+			updates: 1,
+			want: model.Question{
+				Id:      utils.NextID(),
+				Key:     "name",
+				Value:   "value 1",
+				Version: 1,
+				History: []model.Event{
+					model.QuestionAdded{ID: utils.NextID(), Key: "name", Value: "value 0"},
+					model.QuestionUpdate{Key: "name", NewValue: "value 1"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			db, clean := mustOpenDB(t)
+			defer clean(t)
+			s, err := NewService(db, WithSnapshotInterval(tt.interval))
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if _, err := s.New(ctx, "name", "value 0"); err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			for i := 1; i <= tt.updates; i++ {
+				if err := s.Update(ctx, "name", model.Value(fmt.Sprintf("value %d", i))); err != nil {
+					t.Fatalf("got = %v, want nil", err)
+				}
+			}
+			got, err := s.Get(ctx, "name")
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected question mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func BenchmarkServiceGet(b *testing.B) {
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	ctx := context.Background()
+	const updates = 5000
+	for _, interval := range []uint64{0, defaultSnapshotInterval} {
+		b.Run(fmt.Sprintf("snapshot_interval=%d", interval), func(b *testing.B) {
+			db, clean := mustOpenDB(b)
+			defer clean(b)
+			db.NoSync = true
+			s, err := NewService(db, WithSnapshotInterval(interval))
+			if err != nil {
+				b.Fatalf("got = %v, want nil", err)
+			}
+			// Write the log in a single transaction, the same way Update
+			// does one event at a time, to keep the setup fast.
+			err = s.updateTx(ctx, func(tx *tenantTx) error {
+				q := model.New(utils.NextID(), "key", "value")
+				if err := s.put(ctx, tx, q, q.Events()); err != nil {
+					return err
+				}
+				for i := 0; i < updates; i++ {
+					n := len(q.History)
+					if err := q.Update(model.Value(fmt.Sprintf("value %d", i))); err != nil {
+						return err
+					}
+					if err := s.put(ctx, tx, q, q.History[n:]); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				b.Fatalf("got = %v, want nil", err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Get(ctx, "key"); err != nil {
+					b.Fatalf("got = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
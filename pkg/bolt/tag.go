@@ -0,0 +1,163 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+)
+
+var (
+	// tagBucket holds a bucket per tag with the keys of its questions.
+	tagBucket = []byte("tags")
+	// tagDocBucket holds the tags of every key, to unindex them.
+	tagDocBucket = []byte("tag_docs")
+)
+
+// tagSep separates the tags of a key in the tagDocBucket.
+var tagSep = []byte{0}
+
+// indexTags adds the key of q to the buckets of its tags, replacing the
+// previous tags of the key.
+func indexTags(tx *tenantTx, q *model.Question) error {
+	if err := unindexTags(tx, q.Key); err != nil {
+		return err
+	}
+	if len(q.Tags) == 0 {
+		return nil
+	}
+	tBucket := tx.Bucket(tagBucket)
+	dBucket := tx.Bucket(tagDocBucket)
+	if tBucket == nil || dBucket == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	tags := make([][]byte, len(q.Tags))
+	for i, tag := range q.Tags {
+		tags[i] = []byte(tag)
+		b, err := tBucket.CreateBucketIfNotExists(tags[i])
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(q.Key), q.Id); err != nil {
+			return err
+		}
+	}
+	return dBucket.Put([]byte(q.Key), bytes.Join(tags, tagSep))
+}
+
+// unindexTags removes key from the buckets of its tags, and the buckets
+// left empty.
+func unindexTags(tx *tenantTx, key model.Key) error {
+	tBucket := tx.Bucket(tagBucket)
+	dBucket := tx.Bucket(tagDocBucket)
+	if tBucket == nil || dBucket == nil {
+		return fmt.Errorf("bucket not found")
+	}
+	data := dBucket.Get([]byte(key))
+	if len(data) == 0 {
+		return nil
+	}
+	for _, tag := range bytes.Split(data, tagSep) {
+		b := tBucket.Bucket(tag)
+		if b == nil {
+			continue
+		}
+		if err := b.Delete([]byte(key)); err != nil {
+			return err
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			if err := tBucket.DeleteBucket(tag); err != nil {
+				return err
+			}
+		}
+	}
+	return dBucket.Delete([]byte(key))
+}
+
+// hasTags reports whether key has the tags selected by opts.
+func hasTags(tx *tenantTx, key []byte, opts model.ListOptions) bool {
+	if len(opts.Tags) == 0 {
+		return true
+	}
+	tBucket := tx.Bucket(tagBucket)
+	if tBucket == nil {
+		return false
+	}
+	for _, tag := range opts.Tags {
+		b := tBucket.Bucket([]byte(tag))
+		found := b != nil && b.Get(key) != nil
+		if found && opts.AnyTag {
+			return true
+		}
+		if !found && !opts.AnyTag {
+			return false
+		}
+	}
+	return !opts.AnyTag
+}
+
+// Tag adds tag to the question stored at key.
+func (s *service) Tag(ctx context.Context, key model.Key, tag string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Tag")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.Tag(tag)
+	})
+}
+
+// Untag removes tag from the question stored at key.
+func (s *service) Untag(ctx context.Context, key model.Key, tag string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Untag")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.Untag(tag)
+	})
+}
+
+// Tags returns the number of questions not deleted with each tag, ordered
+// by tag. Only the questions visible now and readable by the principal of
+// ctx are counted, the tags without any are left out.
+func (s *service) Tags(ctx context.Context) (_ []model.TagCount, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Tags")
+	now := utils.Clock()
+	var l []model.TagCount
+	err = s.viewTx(ctx, func(tx *tenantTx) error {
+		tBucket := tx.Bucket(tagBucket)
+		if tBucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+		// counted caches whether a key is counted, it has several tags.
+		counted := map[string]bool{}
+		return tBucket.ForEach(func(k, _ []byte) error {
+			b := tBucket.Bucket(k)
+			if b == nil {
+				return nil
+			}
+			n := 0
+			err := b.ForEach(func(key, _ []byte) error {
+				ok, seen := counted[string(key)]
+				if !seen {
+					q, err := load(tx, model.Key(key))
+					if err != nil {
+						return err
+					}
+					ok = q.Visible(now) && readable(ctx, q)
+					counted[string(key)] = ok
+				}
+				if ok {
+					n++
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				l = append(l, model.TagCount{Tag: string(k), Count: n})
+			}
+			return nil
+		})
+	})
+	return l, err
+}
@@ -0,0 +1,125 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceTags(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	tags := map[model.Key][]string{
+		"refunds":  {"billing", "EU"},
+		"invoices": {"billing"},
+		"shipping": {"eu", "logistics"},
+		"returns":  {"logistics"},
+		"privacy":  {"eu"},
+	}
+	for key, l := range tags {
+		if _, err := s.New(ctx, key, "value"); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+		for _, tag := range l {
+			if err := s.Tag(ctx, key, tag); err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+		}
+	}
+	if err := s.Untag(ctx, "returns", "logistics"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Untag(ctx, "returns", "logistics"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if err := s.Delete(ctx, "privacy"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.Rename(ctx, "invoices", "bills"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name string
+		opts model.ListOptions
+		want []model.Key
+	}{
+		{
+			name: "one tag",
+			opts: model.ListOptions{Tags: []string{"billing"}},
+			want: []model.Key{"bills", "refunds"},
+		},
+		{
+			name: "every tag",
+			opts: model.ListOptions{Tags: []string{"billing", "eu"}},
+			want: []model.Key{"refunds"},
+		},
+		{
+			name: "any tag",
+			opts: model.ListOptions{Tags: []string{"billing", "logistics"}, AnyTag: true},
+			want: []model.Key{"bills", "refunds", "shipping"},
+		},
+		{
+			name: "unknown tag",
+			opts: model.ListOptions{Tags: []string{"unknown"}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _, err := s.ListPage(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			var got []model.Key
+			for _, q := range l {
+				got = append(got, q.Key)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected keys mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	want := []model.TagCount{
+		{Tag: "billing", Count: 2},
+		{Tag: "eu", Count: 2},
+		{Tag: "logistics", Count: 1},
+	}
+	got, err := s.Tags(ctx)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected tags mismatch (-want +got):\n%s", diff)
+	}
+
+	// The questions not readable, or outside of their validity window,
+	// aren't counted.
+	if err := s.Grant(ctx, "refunds", "user:alice", model.PermissionAdmin); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.SetValidity(ctx, "shipping", time.Now().Add(24*time.Hour), time.Time{}); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	bob := model.NewContextWithPrincipal(ctx, model.Principal{Name: "bob", Role: model.RoleEditor})
+	got, err = s.Tags(bob)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff([]model.TagCount{{Tag: "billing", Count: 1}}, got); diff != "" {
+		t.Errorf("unexpected tags mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,17 @@
+package bolt
+
+import (
+	"context"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+)
+
+// SetTemplate marks the value of the question stored at key as a template,
+// or as plain text.
+func (s *service) SetTemplate(ctx context.Context, key model.Key, on bool) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.SetTemplate")
+	return s.modify(ctx, key, func(q *model.Question) error {
+		return q.SetTemplate(on)
+	})
+}
@@ -0,0 +1,76 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+)
+
+func TestServiceTemplate(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.NewTemplate(ctx, "price", "{{.product"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if _, err := s.NewTemplate(ctx, "price", "{{.product}} costs {{.price}}"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(ctx, "plain", "{{.product"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name    string
+		change  func() error
+		wantErr bool
+	}{
+		{
+			name:    "update with a malformed template",
+			change:  func() error { return s.Update(ctx, "price", "{{if .product}}") },
+			wantErr: true,
+		},
+		{
+			name:    "translate with a malformed template",
+			change:  func() error { return s.Translate(ctx, "price", "es", "{{.product}") },
+			wantErr: true,
+		},
+		{
+			name:   "update with a template",
+			change: func() error { return s.Update(ctx, "price", "{{.product}} costs {{.price}} a month") },
+		},
+		{
+			name:    "mark a malformed template",
+			change:  func() error { return s.SetTemplate(ctx, "plain", true) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	q, err := s.Get(ctx, "price")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, q.Template, true)
+	checkAsserts(t, q.Version, 2)
+	got, err := model.Render(q.Value, map[string]string{"product": "Pro", "price": "10€"})
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, got, model.Value("Pro costs 10€ a month"))
+}
@@ -0,0 +1,193 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// tenantBucket holds a bucket per tenant, with the same buckets the default
+// tenant has at the root of the database.
+var tenantBucket = []byte("tenants")
+
+var errTenantNotFound = fmt.Errorf("tenant %w", derrors.NotFound)
+
+// tenantPattern matches the valid names of tenants.
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// buckets holds the buckets of a tenant, either the root of the database or
+// a bucket of the tenantBucket.
+type buckets interface {
+	Bucket(name []byte) *bolt.Bucket
+	CreateBucketIfNotExists(name []byte) (*bolt.Bucket, error)
+	DeleteBucket(name []byte) error
+}
+
+// tenantTx is a transaction on the buckets of a tenant.
+type tenantTx struct {
+	*bolt.Tx
+	root buckets
+}
+
+func (tx *tenantTx) Bucket(name []byte) *bolt.Bucket {
+	return tx.root.Bucket(name)
+}
+
+func (tx *tenantTx) CreateBucketIfNotExists(name []byte) (*bolt.Bucket, error) {
+	return tx.root.CreateBucketIfNotExists(name)
+}
+
+func (tx *tenantTx) DeleteBucket(name []byte) error {
+	return tx.root.DeleteBucket(name)
+}
+
+// newTenantTx returns a transaction on the buckets of tenant.
+func newTenantTx(tx *bolt.Tx, tenant string) (*tenantTx, error) {
+	if tenant == "" || tenant == model.DefaultTenant {
+		return &tenantTx{Tx: tx, root: tx}, nil
+	}
+	tBucket := tx.Bucket(tenantBucket)
+	if tBucket == nil {
+		return nil, errors.New("bucket doesn't exist")
+	}
+	b := tBucket.Bucket([]byte(tenant))
+	if b == nil {
+		return nil, errTenantNotFound
+	}
+	return &tenantTx{Tx: tx, root: b}, nil
+}
+
+// checkTenant returns the name of the tenant a principal is bound to,
+// DefaultTenant when empty, or an error when the tenant doesn't exist.
+func checkTenant(tx *bolt.Tx, tenant string) (string, error) {
+	switch tenant {
+	case "", model.DefaultTenant:
+		return model.DefaultTenant, nil
+	case model.AllTenants:
+		return tenant, nil
+	}
+	tBucket := tx.Bucket(tenantBucket)
+	if tBucket == nil {
+		return "", errors.New("bucket doesn't exist")
+	}
+	if tBucket.Bucket([]byte(tenant)) == nil {
+		return "", fmt.Errorf("tenant %q %w", tenant, derrors.NotFound)
+	}
+	return tenant, nil
+}
+
+// updateTx runs fn in a read-write transaction on the buckets of the tenant of
+// ctx.
+func (s *service) updateTx(ctx context.Context, fn func(tx *tenantTx) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		t, err := newTenantTx(tx, model.TenantFromContext(ctx))
+		if err != nil {
+			return err
+		}
+		return fn(t)
+	})
+}
+
+// viewTx runs fn in a read-only transaction on the buckets of the tenant of
+// ctx.
+func (s *service) viewTx(ctx context.Context, fn func(tx *tenantTx) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		t, err := newTenantTx(tx, model.TenantFromContext(ctx))
+		if err != nil {
+			return err
+		}
+		return fn(t)
+	})
+}
+
+// createBuckets creates the buckets of a tenant in b.
+func createBuckets(b buckets) error {
+	for _, name := range [][]byte{
+		questionBucket,
+		deletedQuestionBucket,
+		eventBucket,
+		snapshotBucket,
+		redirectBucket,
+		idBucket,
+		missBucket,
+		aliasBucket,
+	} {
+		if _, err := b.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return createIndexBuckets(b)
+}
+
+// CreateTenant creates the buckets of a new tenant.
+func (s *service) CreateTenant(ctx context.Context, name string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.CreateTenant")
+	if name == model.DefaultTenant {
+		return fmt.Errorf("tenant %q: %w", name, derrors.Conflict)
+	}
+	if !tenantPattern.MatchString(name) {
+		return fmt.Errorf("invalid tenant name %q", name)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		tBucket := tx.Bucket(tenantBucket)
+		if tBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		if tBucket.Bucket([]byte(name)) != nil {
+			return fmt.Errorf("tenant %q: %w", name, derrors.Conflict)
+		}
+		b, err := tBucket.CreateBucket([]byte(name))
+		if err != nil {
+			return err
+		}
+		return createBuckets(b)
+	})
+}
+
+// Tenants returns the names of the tenants, the default one first.
+func (s *service) Tenants(ctx context.Context) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Tenants")
+	l := []string{model.DefaultTenant}
+	err = s.db.View(func(tx *bolt.Tx) error {
+		tBucket := tx.Bucket(tenantBucket)
+		if tBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		return tBucket.ForEach(func(k, _ []byte) error {
+			l = append(l, string(k))
+			return nil
+		})
+	})
+	return l, err
+}
+
+// DeleteTenant deletes a tenant along with all its questions, and the API
+// keys and the users bound to it. The default tenant can't be deleted.
+func (s *service) DeleteTenant(ctx context.Context, name string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.DeleteTenant")
+	if name == "" || name == model.DefaultTenant {
+		return errors.New("default tenant can't be deleted")
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		tBucket := tx.Bucket(tenantBucket)
+		if tBucket == nil {
+			return errors.New("bucket doesn't exist")
+		}
+		if tBucket.Bucket([]byte(name)) == nil {
+			return errTenantNotFound
+		}
+		if err := deleteTenantAPIKeys(tx, name); err != nil {
+			return err
+		}
+		if err := deleteTenantUsers(tx, name); err != nil {
+			return err
+		}
+		return tBucket.DeleteBucket([]byte(name))
+	})
+}
@@ -0,0 +1,101 @@
+package bolt
+
+import (
+	"context"
+	"testing"
+
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceTenants(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	utils.Generator = func() string {
+		return "test_id_generator"
+	}
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, name := range []string{"billing", "support"} {
+		if err := s.CreateTenant(ctx, name); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	for _, name := range []string{"billing", "default", "", "Not Valid"} {
+		if err := s.CreateTenant(ctx, name); err == nil {
+			t.Fatalf("create tenant %q: got = nil, want error", name)
+		}
+	}
+	billing := model.NewContextWithTenant(ctx, "billing")
+	support := model.NewContextWithTenant(ctx, "support")
+	if _, err := s.New(ctx, "refunds", "default value"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.New(billing, "refunds", "billing value"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name    string
+		ctx     context.Context
+		want    model.Value
+		wantErr bool
+	}{
+		{
+			name: "default tenant",
+			ctx:  ctx,
+			want: "default value",
+		},
+		{
+			name: "default tenant by name",
+			ctx:  model.NewContextWithTenant(ctx, model.DefaultTenant),
+			want: "default value",
+		},
+		{
+			name: "tenant",
+			ctx:  billing,
+			want: "billing value",
+		},
+		{
+			name:    "other tenant",
+			ctx:     support,
+			wantErr: true,
+		},
+		{
+			name:    "unknown tenant",
+			ctx:     model.NewContextWithTenant(ctx, "unknown"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := s.Get(tt.ctx, "refunds")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			checkAsserts(t, q.Value, tt.want)
+		})
+	}
+
+	if err := s.DeleteTenant(ctx, "billing"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.DeleteTenant(ctx, model.DefaultTenant); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	if _, err := s.Get(billing, "refunds"); err == nil {
+		t.Fatalf("got = nil, want error")
+	}
+	got, err := s.Tenants(ctx)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if diff := cmp.Diff([]string{"default", "support"}, got); diff != "" {
+		t.Errorf("unexpected tenants mismatch (-want +got):\n%s", diff)
+	}
+}
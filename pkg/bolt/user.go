@@ -0,0 +1,430 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// userBucket holds the users of every tenant by name.
+	userBucket = []byte("users")
+
+	// resetTokenBucket holds the password reset tokens not used yet by the
+	// SHA-256 hash of the token.
+	resetTokenBucket = []byte("reset_tokens")
+)
+
+var (
+	errUserNotFound       = fmt.Errorf("user %w", derrors.NotFound)
+	errInvalidCredentials = errors.New("invalid name or password")
+	errInvalidResetToken  = errors.New("invalid or expired reset token")
+)
+
+// userPattern matches the valid names of users and groups.
+var userPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]{0,127}$`)
+
+const (
+	// minPasswordLength is the length of the shortest password accepted.
+	minPasswordLength = 8
+
+	// resetTokenTTL is the time a password reset token can be used in.
+	resetTokenTTL = 24 * time.Hour
+)
+
+// dummyPassword is hashed once to be compared with the passwords of the
+// unknown users, so they take as long to log in as the others.
+var dummyPassword struct {
+	once sync.Once
+	hash []byte
+}
+
+// dummyHash returns the hash of dummyPassword.
+func dummyHash() []byte {
+	dummyPassword.once.Do(func() {
+		dummyPassword.hash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	})
+	return dummyPassword.hash
+}
+
+// userRecord is a user as stored, with the hash of its password.
+type userRecord struct {
+	model.User
+	Password []byte
+}
+
+// resetToken is a password reset token as stored.
+type resetToken struct {
+	Name      string
+	ExpiresAt time.Time
+}
+
+// CreateUser creates a user with role in tenant, the default one when
+// empty, or in every tenant with model.AllTenants. The user logs in with
+// password.
+func (s *service) CreateUser(ctx context.Context, name, password string, role model.Role, tenant string) (_ model.User, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.CreateUser")
+	if !userPattern.MatchString(name) {
+		return model.User{}, fmt.Errorf("invalid user name %q", name)
+	}
+	if _, err := model.ParseRole(string(role)); err != nil {
+		return model.User{}, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return model.User{}, err
+	}
+	now := utils.Clock().UTC()
+	u := userRecord{
+		User:     model.User{Name: name, Role: role, CreatedAt: now, PasswordChangedAt: now},
+		Password: hash,
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		if u.Tenant, err = checkTenant(tx, tenant); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucketIfNotExists(userBucket)
+		if err != nil {
+			return err
+		}
+		if b.Get([]byte(name)) != nil {
+			return fmt.Errorf("user %q: %w", name, derrors.Conflict)
+		}
+		return putUser(b, u)
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	return u.User, nil
+}
+
+// User returns the user called name.
+func (s *service) User(ctx context.Context, name string) (_ model.User, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.User")
+	var u userRecord
+	err = s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		u, err = getUser(tx.Bucket(userBucket), name)
+		return err
+	})
+	return u.User, err
+}
+
+// Users returns the users, ordered by name.
+func (s *service) Users(ctx context.Context) (_ []model.User, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Users")
+	var l []model.User
+	err = s.db.View(func(tx *bolt.Tx) error {
+		return forEachUser(tx.Bucket(userBucket), func(u userRecord) error {
+			l = append(l, u.User)
+			return nil
+		})
+	})
+	return l, err
+}
+
+// SetUserRole changes the role of the user called name.
+func (s *service) SetUserRole(ctx context.Context, name string, role model.Role) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.SetUserRole")
+	if _, err := model.ParseRole(string(role)); err != nil {
+		return err
+	}
+	return s.updateUser(name, func(u *userRecord) error {
+		u.Role = role
+		return nil
+	})
+}
+
+// DeleteUser deletes the user called name and its reset tokens.
+func (s *service) DeleteUser(ctx context.Context, name string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.DeleteUser")
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(userBucket)
+		if _, err := getUser(b, name); err != nil {
+			return err
+		}
+		if err := b.Delete([]byte(name)); err != nil {
+			return err
+		}
+		return deleteResetTokens(tx, name)
+	})
+}
+
+// AddMember adds the user called name to group.
+func (s *service) AddMember(ctx context.Context, group, name string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.AddMember")
+	if !userPattern.MatchString(group) {
+		return fmt.Errorf("invalid group name %q", group)
+	}
+	return s.updateUser(name, func(u *userRecord) error {
+		i := sort.SearchStrings(u.Groups, group)
+		if i < len(u.Groups) && u.Groups[i] == group {
+			return fmt.Errorf("user %q already member of %q: %w", name, group, derrors.Conflict)
+		}
+		u.Groups = append(u.Groups, "")
+		copy(u.Groups[i+1:], u.Groups[i:])
+		u.Groups[i] = group
+		return nil
+	})
+}
+
+// RemoveMember removes the user called name from group.
+func (s *service) RemoveMember(ctx context.Context, group, name string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.RemoveMember")
+	return s.updateUser(name, func(u *userRecord) error {
+		i := sort.SearchStrings(u.Groups, group)
+		if i == len(u.Groups) || u.Groups[i] != group {
+			return fmt.Errorf("user %q member of %q %w", name, group, derrors.NotFound)
+		}
+		u.Groups = append(u.Groups[:i:i], u.Groups[i+1:]...)
+		return nil
+	})
+}
+
+// Groups returns the groups having members, ordered by name.
+func (s *service) Groups(ctx context.Context) (_ []model.Group, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Groups")
+	members := map[string][]string{}
+	err = s.db.View(func(tx *bolt.Tx) error {
+		return forEachUser(tx.Bucket(userBucket), func(u userRecord) error {
+			for _, g := range u.Groups {
+				members[g] = append(members[g], u.Name)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	l := make([]model.Group, 0, len(members))
+	for name, m := range members {
+		l = append(l, model.Group{Name: name, Members: m})
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l, nil
+}
+
+// Login returns the user called name when password is its password. The
+// password is compared with a hash even when the user doesn't exist, not to
+// tell by the time taken which users do.
+func (s *service) Login(ctx context.Context, name, password string) (_ model.User, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.Login")
+	var u userRecord
+	err = s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		u, err = getUser(tx.Bucket(userBucket), name)
+		return err
+	})
+	if errors.Is(err, errUserNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return model.User{}, errInvalidCredentials
+	}
+	if err != nil {
+		return model.User{}, err
+	}
+	if err := bcrypt.CompareHashAndPassword(u.Password, []byte(password)); err != nil {
+		return model.User{}, errInvalidCredentials
+	}
+	return u.User, nil
+}
+
+// CreateResetToken returns a token resetting the password of the user called
+// name once, for a day.
+func (s *service) CreateResetToken(ctx context.Context, name string) (_ string, err error) {
+	defer derrors.WrapStack(&err, "bolt.service.CreateResetToken")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := getUser(tx.Bucket(userBucket), name); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucketIfNotExists(resetTokenBucket)
+		if err != nil {
+			return err
+		}
+		var data bytes.Buffer
+		t := resetToken{Name: name, ExpiresAt: utils.Clock().Add(resetTokenTTL).UTC()}
+		if err := gob.NewEncoder(&data).Encode(t); err != nil {
+			return err
+		}
+		return b.Put(hashToken(token), data.Bytes())
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ResetPassword sets the password of the user a reset token was created
+// for, and invalidates the token along with the tokens issued to the user
+// before.
+func (s *service) ResetPassword(ctx context.Context, token, password string) (err error) {
+	defer derrors.WrapStack(&err, "bolt.service.ResetPassword")
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	// An expired token is deleted, the transaction has to commit.
+	expired := false
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(resetTokenBucket)
+		if b == nil {
+			return errInvalidResetToken
+		}
+		data := b.Get(hashToken(token))
+		if data == nil {
+			return errInvalidResetToken
+		}
+		var t resetToken
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&t); err != nil {
+			return err
+		}
+		if err := b.Delete(hashToken(token)); err != nil {
+			return err
+		}
+		if expired = !utils.Clock().Before(t.ExpiresAt); expired {
+			return nil
+		}
+		uBucket := tx.Bucket(userBucket)
+		u, err := getUser(uBucket, t.Name)
+		if err != nil {
+			return err
+		}
+		u.Password = hash
+		u.PasswordChangedAt = utils.Clock().UTC()
+		u.PasswordGeneration++
+		return putUser(uBucket, u)
+	})
+	if err == nil && expired {
+		err = errInvalidResetToken
+	}
+	return err
+}
+
+// updateUser applies fn to the user called name and stores it.
+func (s *service) updateUser(name string, fn func(u *userRecord) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(userBucket)
+		u, err := getUser(b, name)
+		if err != nil {
+			return err
+		}
+		if err := fn(&u); err != nil {
+			return err
+		}
+		return putUser(b, u)
+	})
+}
+
+// deleteResetTokens deletes the reset tokens of the user called name.
+func deleteResetTokens(tx *bolt.Tx, name string) error {
+	b := tx.Bucket(resetTokenBucket)
+	if b == nil {
+		return nil
+	}
+	var keys [][]byte
+	err := b.ForEach(func(k, v []byte) error {
+		var t resetToken
+		if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&t); err != nil {
+			return err
+		}
+		if t.Name == name {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteTenantUsers deletes the users bound to tenant and their reset
+// tokens.
+func deleteTenantUsers(tx *bolt.Tx, tenant string) error {
+	b := tx.Bucket(userBucket)
+	var names []string
+	err := forEachUser(b, func(u userRecord) error {
+		if u.Tenant == tenant {
+			names = append(names, u.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := b.Delete([]byte(name)); err != nil {
+			return err
+		}
+		if err := deleteResetTokens(tx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hashPassword(password string) ([]byte, error) {
+	if len(password) < minPasswordLength {
+		return nil, fmt.Errorf("password shorter than %d characters", minPasswordLength)
+	}
+	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+}
+
+func getUser(b *bolt.Bucket, name string) (userRecord, error) {
+	var u userRecord
+	if b == nil {
+		return u, errUserNotFound
+	}
+	data := b.Get([]byte(name))
+	if data == nil {
+		return u, errUserNotFound
+	}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&u)
+	return u, err
+}
+
+func putUser(b *bolt.Bucket, u userRecord) error {
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(u); err != nil {
+		return err
+	}
+	return b.Put([]byte(u.Name), data.Bytes())
+}
+
+func forEachUser(b *bolt.Bucket, fn func(u userRecord) error) error {
+	if b == nil {
+		return nil
+	}
+	return b.ForEach(func(_, v []byte) error {
+		var u userRecord
+		if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&u); err != nil {
+			return err
+		}
+		return fn(u)
+	})
+}
@@ -0,0 +1,202 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"answer.io/pkg/derrors"
+	"answer.io/pkg/model"
+	"answer.io/pkg/utils"
+
+	"github.com/google/go-cmp/cmp"
+	bbolt "go.etcd.io/bbolt"
+)
+
+func TestServiceUsers(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.CreateTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, u := range []struct{ name, tenant string }{
+		{"alice", ""},
+		{"bob", "acme"},
+	} {
+		if _, err := s.CreateUser(ctx, u.name, "password-"+u.name, model.RoleReader, u.tenant); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if _, err := s.CreateUser(ctx, "alice", "password-alice", model.RoleReader, ""); !errors.Is(err, derrors.Conflict) {
+		t.Fatalf("got = %v, want conflict", err)
+	}
+	if _, err := s.CreateUser(ctx, "carol", "short", model.RoleReader, ""); err == nil {
+		t.Fatal("got = nil, want error for a short password")
+	}
+	if _, err := s.CreateUser(ctx, "carol", "password-carol", model.RoleReader, "missing"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+	if err := s.SetUserRole(ctx, "alice", model.RoleEditor); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	for _, m := range []struct{ group, name string }{
+		{"sales", "alice"},
+		{"eng", "alice"},
+		{"sales", "bob"},
+	} {
+		if err := s.AddMember(ctx, m.group, m.name); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := s.AddMember(ctx, "sales", "carol"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+	if err := s.RemoveMember(ctx, "sales", "bob"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+
+	var testCases = []struct {
+		name     string
+		user     string
+		password string
+		want     model.User
+		wantErr  bool
+	}{
+		{
+			name:     "login",
+			user:     "alice",
+			password: "password-alice",
+			want: model.User{
+				Name:              "alice",
+				Role:              model.RoleEditor,
+				Groups:            []string{"eng", "sales"},
+				Tenant:            model.DefaultTenant,
+				CreatedAt:         now,
+				PasswordChangedAt: now,
+			},
+		},
+		{
+			name:     "wrong password",
+			user:     "alice",
+			password: "password-bob",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown user",
+			user:     "carol",
+			password: "password-carol",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Login(ctx, tt.user, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected user mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	groups, err := s.Groups(ctx)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	wantGroups := []model.Group{
+		{Name: "eng", Members: []string{"alice"}},
+		{Name: "sales", Members: []string{"alice"}},
+	}
+	if diff := cmp.Diff(wantGroups, groups); diff != "" {
+		t.Errorf("unexpected groups mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := s.DeleteUser(ctx, "alice"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.User(ctx, "alice"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+	users, err := s.Users(ctx)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	wantUsers := []model.User{{Name: "bob", Role: model.RoleReader, Tenant: "acme", CreatedAt: now, PasswordChangedAt: now}}
+	if diff := cmp.Diff(wantUsers, users); diff != "" {
+		t.Errorf("unexpected users mismatch (-want +got):\n%s", diff)
+	}
+
+	// The users of a tenant are deleted along with it.
+	if err := s.DeleteTenant(ctx, "acme"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.User(ctx, "bob"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+}
+
+func TestServiceResetPassword(t *testing.T) {
+	db, clean := mustOpenDB(t)
+	defer clean(t)
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	utils.Clock = func() time.Time { return now }
+	defer func() { utils.Clock = time.Now }()
+	s, err := NewService(db)
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.CreateUser(ctx, "alice", "old password", model.RoleReader, ""); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	token, err := s.CreateResetToken(ctx, "alice")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	expired, err := s.CreateResetToken(ctx, "alice")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.CreateResetToken(ctx, "bob"); !errors.Is(err, derrors.NotFound) {
+		t.Fatalf("got = %v, want not found", err)
+	}
+	now = now.Add(time.Minute)
+	resetAt := now
+	if err := s.ResetPassword(ctx, token, "new password"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := s.ResetPassword(ctx, token, "other password"); err == nil {
+		t.Fatal("got = nil, want error for a token used twice")
+	}
+	now = now.Add(resetTokenTTL)
+	if err := s.ResetPassword(ctx, expired, "other password"); err == nil {
+		t.Fatal("got = nil, want error for an expired token")
+	}
+	// The tokens used or expired are deleted.
+	err = db.View(func(tx *bbolt.Tx) error {
+		checkAsserts(t, tx.Bucket(resetTokenBucket).Stats().KeyN, 0)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if _, err := s.Login(ctx, "alice", "old password"); err == nil {
+		t.Fatal("got = nil, want error for the old password")
+	}
+	u, err := s.Login(ctx, "alice", "new password")
+	if err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	checkAsserts(t, u.PasswordChangedAt, resetAt)
+	checkAsserts(t, u.PasswordGeneration, 1)
+}
@@ -8,6 +8,19 @@ import (
 	"runtime"
 )
 
+var (
+	// NotFound indicates that a requested resource was not found.
+	NotFound = errors.New("not found")
+
+	// Conflict indicates that the state of a resource doesn't match the
+	// one expected by the caller.
+	Conflict = errors.New("conflict")
+
+	// Forbidden indicates that the caller isn't allowed to access a
+	// resource.
+	Forbidden = errors.New("forbidden")
+)
+
 // Add adds context to the error.
 // The result cannot be unwrapped to recover the original error.
 // It does nothing when *errp == nil.
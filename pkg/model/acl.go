@@ -0,0 +1,194 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permission is what a grant of an ACL allows on a question, every
+// permission allows the permissions below it.
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+	// PermissionAdmin allows to change the ACL.
+	PermissionAdmin Permission = "admin"
+)
+
+// permissionRanks orders the permissions.
+var permissionRanks = map[Permission]int{
+	PermissionRead:  1,
+	PermissionWrite: 2,
+	PermissionAdmin: 3,
+}
+
+// ParsePermission returns the permission named s.
+func ParsePermission(s string) (Permission, error) {
+	p := Permission(s)
+	if _, ok := permissionRanks[p]; !ok {
+		return "", fmt.Errorf("invalid permission %q, expected read, write or admin", s)
+	}
+	return p, nil
+}
+
+// Allows reports whether p allows required.
+func (p Permission) Allows(required Permission) bool {
+	rank, ok := permissionRanks[p]
+	return ok && rank >= permissionRanks[required]
+}
+
+// The prefixes of the subjects of the grants.
+const (
+	SubjectUser  = "user:"
+	SubjectGroup = "group:"
+)
+
+// ParseSubject checks that s names a user or a group, as user:name or
+// group:name.
+func ParseSubject(s string) (string, error) {
+	for _, prefix := range []string{SubjectUser, SubjectGroup} {
+		if name := strings.TrimPrefix(s, prefix); name != s && strings.TrimSpace(name) != "" {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid subject %q, expected user:name or group:name", s)
+}
+
+// Grant gives a permission on a question to a subject.
+type Grant struct {
+	Subject    string     `json:"subject"`
+	Permission Permission `json:"permission"`
+}
+
+// Access tells whether a principal is allowed a permission on a question,
+// and why.
+type Access struct {
+	Permission Permission `json:"permission"`
+	Allowed    bool       `json:"allowed"`
+	Reason     string     `json:"reason"`
+	// Grants are the grants of the ACL matching the principal.
+	Grants []Grant `json:"grants,omitempty"`
+}
+
+// rolePermissions are the permissions of the roles on the questions without
+// ACL.
+var rolePermissions = map[Role]Permission{
+	RoleReader: PermissionRead,
+	RoleEditor: PermissionAdmin,
+	RoleAdmin:  PermissionAdmin,
+}
+
+// Access returns whether p is allowed required on q. Administrators are
+// allowed everything. The questions without ACL are read by the readers
+// and written by the editors, the others are only accessed through the
+// grants of their ACL matching p or one of its groups.
+func (q Question) Access(p Principal, required Permission) Access {
+	a := Access{Permission: required}
+	name := p.Name
+	if name == "" {
+		name = "anonymous"
+	}
+	if p.Role == RoleAdmin {
+		a.Allowed = true
+		a.Reason = fmt.Sprintf("%s is an administrator", name)
+		return a
+	}
+	if len(q.ACL) == 0 {
+		a.Allowed = rolePermissions[p.Role].Allows(required)
+		a.Reason = fmt.Sprintf("%s has the %s role and the question has no ACL", name, p.Role)
+		if !a.Allowed {
+			role := RoleEditor
+			if required == PermissionRead {
+				role = RoleReader
+			}
+			a.Reason += fmt.Sprintf(", %s requires the %s role", required, role)
+		}
+		return a
+	}
+	subjects := p.Subjects()
+	for _, g := range q.ACL {
+		for _, s := range subjects {
+			if g.Subject != s {
+				continue
+			}
+			a.Grants = append(a.Grants, g)
+			if g.Permission.Allows(required) {
+				a.Allowed = true
+			}
+		}
+	}
+	switch {
+	case a.Allowed:
+		a.Reason = fmt.Sprintf("the ACL of the question grants %s to %s", required, name)
+	case len(a.Grants) > 0:
+		a.Reason = fmt.Sprintf("the ACL of the question grants %s less than %s", name, required)
+	default:
+		a.Reason = fmt.Sprintf("the ACL of the question has no grant for %s or its groups", name)
+	}
+	return a
+}
+
+// Can reports whether p is allowed required on q.
+func (q Question) Can(p Principal, required Permission) bool {
+	return q.Access(p, required).Allowed
+}
+
+// Grant gives permission on q to subject, replacing its previous grant.
+func (q *Question) Grant(subject string, permission Permission) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if _, err := ParseSubject(subject); err != nil {
+		return err
+	}
+	if _, err := ParsePermission(string(permission)); err != nil {
+		return err
+	}
+	if g := q.grant(subject); g != nil && g.Permission == permission {
+		return fmt.Errorf("%s already granted %s", subject, permission)
+	}
+	q.raise(AccessGranted{Key: q.Key, Subject: subject, Permission: permission})
+	return nil
+}
+
+// Revoke removes the grant of subject from the ACL of q.
+func (q *Question) Revoke(subject string) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if q.grant(subject) == nil {
+		return fmt.Errorf("grant of %s not found", subject)
+	}
+	q.raise(AccessRevoked{Key: q.Key, Subject: subject})
+	return nil
+}
+
+func (q *Question) grant(subject string) *Grant {
+	for i := range q.ACL {
+		if q.ACL[i].Subject == subject {
+			return &q.ACL[i]
+		}
+	}
+	return nil
+}
+
+func (q *Question) onACL(ev Event) {
+	switch e := ev.(type) {
+	case AccessGranted:
+		if g := q.grant(e.Subject); g != nil {
+			g.Permission = e.Permission
+			return
+		}
+		q.ACL = append(q.ACL, Grant{Subject: e.Subject, Permission: e.Permission})
+		sort.Slice(q.ACL, func(i, j int) bool { return q.ACL[i].Subject < q.ACL[j].Subject })
+	case AccessRevoked:
+		for i, g := range q.ACL {
+			if g.Subject == e.Subject {
+				q.ACL = append(q.ACL[:i:i], q.ACL[i+1:]...)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package model
+
+import "testing"
+
+func TestQuestionAccess(t *testing.T) {
+	open := Question{Key: "refunds"}
+	restricted := Question{
+		Key: "pricing",
+		ACL: []Grant{
+			{Subject: "group:sales", Permission: PermissionRead},
+			{Subject: "user:bob", Permission: PermissionWrite},
+		},
+	}
+	var testCases = []struct {
+		name       string
+		q          Question
+		p          Principal
+		permission Permission
+		want       bool
+	}{
+		{
+			name:       "reader reads a question without ACL",
+			q:          open,
+			p:          Principal{Name: "alice", Role: RoleReader},
+			permission: PermissionRead,
+			want:       true,
+		},
+		{
+			name:       "reader writes a question without ACL",
+			q:          open,
+			p:          Principal{Name: "alice", Role: RoleReader},
+			permission: PermissionWrite,
+		},
+		{
+			name:       "editor administers a question without ACL",
+			q:          open,
+			p:          Principal{Name: "alice", Role: RoleEditor},
+			permission: PermissionAdmin,
+			want:       true,
+		},
+		{
+			name:       "editor without grant",
+			q:          restricted,
+			p:          Principal{Name: "alice", Role: RoleEditor},
+			permission: PermissionRead,
+		},
+		{
+			name:       "group grant",
+			q:          restricted,
+			p:          Principal{Name: "alice", Role: RoleReader, Groups: []string{"sales"}},
+			permission: PermissionRead,
+			want:       true,
+		},
+		{
+			name:       "group grant below the permission",
+			q:          restricted,
+			p:          Principal{Name: "alice", Role: RoleEditor, Groups: []string{"sales"}},
+			permission: PermissionWrite,
+		},
+		{
+			name:       "user grant",
+			q:          restricted,
+			p:          Principal{Name: "bob", Role: RoleEditor},
+			permission: PermissionWrite,
+			want:       true,
+		},
+		{
+			name:       "administrator",
+			q:          restricted,
+			p:          Principal{Name: "root", Role: RoleAdmin},
+			permission: PermissionAdmin,
+			want:       true,
+		},
+		{
+			name:       "anonymous",
+			q:          restricted,
+			p:          Principal{Role: RoleReader},
+			permission: PermissionRead,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.q.Access(tt.p, tt.permission)
+			if a.Allowed != tt.want {
+				t.Errorf("got = %v (%s), want %v", a.Allowed, a.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuestionGrant(t *testing.T) {
+	q := New(nil, "pricing", "secret")
+	for _, g := range []Grant{
+		{Subject: "user:bob", Permission: PermissionRead},
+		{Subject: "group:sales", Permission: PermissionRead},
+		{Subject: "user:bob", Permission: PermissionWrite},
+	} {
+		if err := q.Grant(g.Subject, g.Permission); err != nil {
+			t.Fatalf("got = %v, want nil", err)
+		}
+	}
+	if err := q.Grant("user:bob", PermissionWrite); err == nil {
+		t.Fatal("got = nil, want error for a grant already made")
+	}
+	if err := q.Grant("bob", PermissionRead); err == nil {
+		t.Fatal("got = nil, want error for an invalid subject")
+	}
+	if err := q.Revoke("group:sales"); err != nil {
+		t.Fatalf("got = %v, want nil", err)
+	}
+	if err := q.Revoke("group:sales"); err == nil {
+		t.Fatal("got = nil, want error for a missing grant")
+	}
+	want := []Grant{{Subject: "user:bob", Permission: PermissionWrite}}
+	if len(q.ACL) != len(want) || q.ACL[0] != want[0] {
+		t.Errorf("got = %v, want %v", q.ACL, want)
+	}
+	if q.Version != 4 {
+		t.Errorf("got version %d, want 4", q.Version)
+	}
+}
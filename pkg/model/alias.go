@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AddAlias adds alias as another key of q.
+func (q *Question) AddAlias(alias Key) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if alias == "" {
+		return fmt.Errorf("empty alias")
+	}
+	if alias == q.Key {
+		return fmt.Errorf("alias %q is the key of the question", alias)
+	}
+	if q.HasAlias(alias) {
+		return fmt.Errorf("alias %q already exist", alias)
+	}
+	q.raise(AliasAdded{Key: q.Key, Alias: alias})
+	return nil
+}
+
+func (q *Question) RemoveAlias(alias Key) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if !q.HasAlias(alias) {
+		return fmt.Errorf("alias %q not found", alias)
+	}
+	q.raise(AliasRemoved{Key: q.Key, Alias: alias})
+	return nil
+}
+
+// HasAlias reports whether alias is another key of q.
+func (q *Question) HasAlias(alias Key) bool {
+	i := sort.Search(len(q.Aliases), func(i int) bool { return q.Aliases[i] >= alias })
+	return i < len(q.Aliases) && q.Aliases[i] == alias
+}
+
+func (q *Question) onAlias(ev Event) {
+	switch e := ev.(type) {
+	case AliasAdded:
+		if q.HasAlias(e.Alias) {
+			return
+		}
+		i := sort.Search(len(q.Aliases), func(i int) bool { return q.Aliases[i] >= e.Alias })
+		q.Aliases = append(q.Aliases, "")
+		copy(q.Aliases[i+1:], q.Aliases[i:])
+		q.Aliases[i] = e.Alias
+	case AliasRemoved:
+		for i, alias := range q.Aliases {
+			if alias == e.Alias {
+				q.Aliases = append(q.Aliases[:i:i], q.Aliases[i+1:]...)
+				break
+			}
+		}
+	}
+}
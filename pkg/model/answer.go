@@ -0,0 +1,172 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+
+	"answer.io/pkg/utils"
+)
+
+// Answer is one of the candidate answers of a question.
+type Answer struct {
+	ID     utils.ID `json:"id"`
+	Author string   `json:"author"`
+	Value  Value    `json:"value"`
+	Votes  int      `json:"votes"`
+	// Voters holds the vote, 1 or -1, of everyone who voted the answer.
+	Voters map[string]int `json:"-"`
+}
+
+// Answer returns the answer of q identified by id, nil if there is none.
+func (q *Question) Answer(id utils.ID) *Answer {
+	if len(id) == 0 {
+		return nil
+	}
+	for i := range q.Answers {
+		if bytes.Equal(q.Answers[i].ID, id) {
+			return &q.Answers[i]
+		}
+	}
+	return nil
+}
+
+func (q *Question) AddAnswer(id utils.ID, author string, value Value) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if q.Answer(id) != nil {
+		return fmt.Errorf("answer already exist")
+	}
+	if err := q.checkValue(value); err != nil {
+		return err
+	}
+	q.raise(AnswerAdded{
+		Key:      q.Key,
+		AnswerID: id,
+		Author:   author,
+		Value:    value,
+	})
+	return nil
+}
+
+func (q *Question) EditAnswer(id utils.ID, value Value) error {
+	if err := q.checkAnswer(id); err != nil {
+		return err
+	}
+	if err := q.checkValue(value); err != nil {
+		return err
+	}
+	q.raise(AnswerEdited{
+		Key:      q.Key,
+		AnswerID: id,
+		Value:    value,
+	})
+	return nil
+}
+
+func (q *Question) RemoveAnswer(id utils.ID) error {
+	if err := q.checkAnswer(id); err != nil {
+		return err
+	}
+	if bytes.Equal(q.Accepted, id) {
+		return fmt.Errorf("answer accepted, accept another one first")
+	}
+	q.raise(AnswerRemoved{
+		Key:      q.Key,
+		AnswerID: id,
+	})
+	return nil
+}
+
+// Vote records the vote of voter for an answer: 1 for, -1 against, or 0 to
+// withdraw a previous vote.
+func (q *Question) Vote(id utils.ID, voter string, vote int) error {
+	if err := q.checkAnswer(id); err != nil {
+		return err
+	}
+	if voter == "" {
+		return fmt.Errorf("anonymous vote")
+	}
+	if vote < -1 || vote > 1 {
+		return fmt.Errorf("invalid vote %d", vote)
+	}
+	if q.Answer(id).Voters[voter] == vote {
+		return nil
+	}
+	q.raise(AnswerVoted{
+		Key:      q.Key,
+		AnswerID: id,
+		Voter:    voter,
+		Vote:     vote,
+	})
+	return nil
+}
+
+// Accept makes an answer the value of the question.
+func (q *Question) Accept(id utils.ID) error {
+	if err := q.checkAnswer(id); err != nil {
+		return err
+	}
+	if err := q.checkValue(q.Answer(id).Value); err != nil {
+		return err
+	}
+	q.raise(AnswerAccepted{
+		Key:      q.Key,
+		AnswerID: id,
+	})
+	return nil
+}
+
+func (q *Question) checkAnswer(id utils.ID) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if q.Answer(id) == nil {
+		return fmt.Errorf("answer not found")
+	}
+	return nil
+}
+
+func (q *Question) onAnswer(ev Event) {
+	switch e := ev.(type) {
+	case AnswerAdded:
+		q.Answers = append(q.Answers, Answer{
+			ID:     e.AnswerID,
+			Author: e.Author,
+			Value:  e.Value,
+		})
+	case AnswerEdited:
+		if a := q.Answer(e.AnswerID); a != nil {
+			a.Value = e.Value
+		}
+		if bytes.Equal(q.Accepted, e.AnswerID) {
+			q.Value = e.Value
+		}
+	case AnswerRemoved:
+		for i := range q.Answers {
+			if bytes.Equal(q.Answers[i].ID, e.AnswerID) {
+				q.Answers = append(q.Answers[:i:i], q.Answers[i+1:]...)
+				break
+			}
+		}
+	case AnswerVoted:
+		a := q.Answer(e.AnswerID)
+		if a == nil {
+			return
+		}
+		a.Votes += e.Vote - a.Voters[e.Voter]
+		if e.Vote == 0 {
+			delete(a.Voters, e.Voter)
+			return
+		}
+		if a.Voters == nil {
+			a.Voters = map[string]int{}
+		}
+		a.Voters[e.Voter] = e.Vote
+	case AnswerAccepted:
+		if a := q.Answer(e.AnswerID); a != nil {
+			q.Accepted = e.AnswerID
+			q.Value = a.Value
+		}
+	}
+}
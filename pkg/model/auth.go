@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Role grants access to the routes of the server, every role grants the
+// access of the roles below it.
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// roleRanks orders the roles.
+var roleRanks = map[Role]int{
+	RoleReader: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ParseRole returns the role named s.
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if _, ok := roleRanks[r]; !ok {
+		return "", fmt.Errorf("invalid role %q, expected reader, editor or admin", s)
+	}
+	return r, nil
+}
+
+// Allows reports whether r grants the access of required.
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRanks[r]
+	return ok && rank >= roleRanks[required]
+}
+
+// AllTenants binds a principal to every tenant.
+const AllTenants = "*"
+
+// Principal is who a request is authenticated as.
+type Principal struct {
+	Name   string   `json:"name"`
+	Role   Role     `json:"role"`
+	Groups []string `json:"groups,omitempty"`
+	// Tenant is the tenant p is bound to, DefaultTenant when empty, or
+	// AllTenants.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// InTenant reports whether p is bound to tenant, the default one when
+// empty. Only the principals bound to every tenant are bound to AllTenants.
+func (p Principal) InTenant(tenant string) bool {
+	return p.Tenant == AllTenants || tenantName(p.Tenant) == tenantName(tenant)
+}
+
+// tenantName returns the name of tenant, DefaultTenant when empty.
+func tenantName(tenant string) string {
+	if tenant == "" {
+		return DefaultTenant
+	}
+	return tenant
+}
+
+// Subjects returns the subjects of the grants matching p.
+func (p Principal) Subjects() []string {
+	var l []string
+	if p.Name != "" {
+		l = append(l, SubjectUser+p.Name)
+	}
+	for _, g := range p.Groups {
+		l = append(l, SubjectGroup+g)
+	}
+	return l
+}
+
+// APIKeyPrefix starts every API key, telling them apart from other tokens.
+const APIKeyPrefix = "ak_"
+
+// APIKey describes a static key authenticating a principal. The key itself
+// is only known when it is created.
+type APIKey struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+	// Tenant is the tenant the key is bound to, or AllTenants.
+	Tenant    string    `json:"tenant"`
+	CreatedAt time.Time `json:"created_at"`
+}
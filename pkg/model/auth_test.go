@@ -0,0 +1,54 @@
+package model
+
+import "testing"
+
+func TestPrincipalInTenant(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		p      Principal
+		tenant string
+		want   bool
+	}{
+		{
+			name:   "default tenant",
+			p:      Principal{Name: "alice"},
+			tenant: "",
+			want:   true,
+		},
+		{
+			name:   "default tenant by name",
+			p:      Principal{Name: "alice"},
+			tenant: DefaultTenant,
+			want:   true,
+		},
+		{
+			name:   "other tenant",
+			p:      Principal{Name: "alice"},
+			tenant: "acme",
+		},
+		{
+			name:   "own tenant",
+			p:      Principal{Name: "alice", Tenant: "acme"},
+			tenant: "acme",
+			want:   true,
+		},
+		{
+			name:   "every tenant",
+			p:      Principal{Name: "root", Tenant: AllTenants},
+			tenant: "acme",
+			want:   true,
+		},
+		{
+			name:   "routes of every tenant",
+			p:      Principal{Name: "alice", Tenant: "acme"},
+			tenant: AllTenants,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.InTenant(tt.tenant); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package model
+
+import "context"
+
+type (
+	// actorKey is the type of the context key for the actor.
+	actorKey struct{}
+
+	// causationKey is the type of the context key for the causation ID.
+	causationKey struct{}
+
+	// sourceKey is the type of the context key for the source of a lookup.
+	sourceKey struct{}
+)
+
+// NewContextWithActor creates a new context from ctx that adds the actor
+// recorded on the events raised with it.
+func NewContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, if any.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// NewContextWithCausationID creates a new context from ctx that adds the ID
+// of the request that caused the events raised with it.
+func NewContextWithCausationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, causationKey{}, id)
+}
+
+// CausationIDFromContext returns the causation ID stored in ctx, if any.
+func CausationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(causationKey{}).(string)
+	return id
+}
+
+// NewContextWithSource creates a new context from ctx that adds a
+// description of where the lookups made with it come from, kept as a sample
+// when they miss.
+func NewContextWithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFromContext returns the source of the lookups stored in ctx, if any.
+func SourceFromContext(ctx context.Context) string {
+	source, _ := ctx.Value(sourceKey{}).(string)
+	return source
+}
+
+// DefaultTenant is the name of the tenant of the contexts without one.
+const DefaultTenant = "default"
+
+// tenantKey is the type of the context key for the tenant.
+type tenantKey struct{}
+
+// NewContextWithTenant creates a new context from ctx that adds the tenant
+// whose questions are used with it.
+func NewContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored in ctx, DefaultTenant if none.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, _ := ctx.Value(tenantKey{}).(string); tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// principalKey is the type of the context key for the principal.
+type principalKey struct{}
+
+// NewContextWithPrincipal creates a new context from ctx that adds the
+// principal the requests made with it are authenticated as.
+func NewContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
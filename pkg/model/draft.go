@@ -0,0 +1,167 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+
+	"answer.io/pkg/utils"
+)
+
+// DraftStatus is the step of the review of a draft.
+type DraftStatus string
+
+const (
+	DraftStatusProposed DraftStatus = "proposed"
+	DraftStatusApproved DraftStatus = "approved"
+)
+
+// Draft is a change of the value of a question waiting to be reviewed and
+// published. Rejected and published drafts are only kept in the history.
+type Draft struct {
+	ID       utils.ID    `json:"id"`
+	Author   string      `json:"author"`
+	Value    Value       `json:"value"`
+	Status   DraftStatus `json:"status"`
+	Reviewer string      `json:"reviewer"`
+	Comment  string      `json:"comment"`
+}
+
+// Draft returns the open draft of q identified by id, nil if there is none.
+func (q *Question) Draft(id utils.ID) *Draft {
+	if len(id) == 0 {
+		return nil
+	}
+	for i := range q.Drafts {
+		if bytes.Equal(q.Drafts[i].ID, id) {
+			return &q.Drafts[i]
+		}
+	}
+	return nil
+}
+
+// ProposeDraft proposes value as the next value of q.
+func (q *Question) ProposeDraft(id utils.ID, author string, value Value) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if q.Draft(id) != nil {
+		return fmt.Errorf("draft already exist")
+	}
+	if err := q.checkValue(value); err != nil {
+		return err
+	}
+	q.raise(DraftCreated{
+		Key:     q.Key,
+		DraftID: id,
+		Author:  author,
+		Value:   value,
+	})
+	return nil
+}
+
+// ApproveDraft approves a proposed draft. The reviewer can't be its author.
+func (q *Question) ApproveDraft(id utils.ID, reviewer, comment string) error {
+	d, err := q.checkDraft(id, reviewer)
+	if err != nil {
+		return err
+	}
+	if d.Status != DraftStatusProposed {
+		return fmt.Errorf("draft %s", d.Status)
+	}
+	if d.Author != "" && d.Author == reviewer {
+		return fmt.Errorf("draft can't be approved by its author")
+	}
+	q.raise(DraftApproved{
+		Key:      q.Key,
+		DraftID:  id,
+		Reviewer: reviewer,
+		Comment:  comment,
+	})
+	return nil
+}
+
+// RejectDraft closes a draft without publishing it.
+func (q *Question) RejectDraft(id utils.ID, reviewer, comment string) error {
+	if _, err := q.checkDraft(id, reviewer); err != nil {
+		return err
+	}
+	q.raise(DraftRejected{
+		Key:      q.Key,
+		DraftID:  id,
+		Reviewer: reviewer,
+		Comment:  comment,
+	})
+	return nil
+}
+
+// PublishDraft makes the value of an approved draft the value of q.
+func (q *Question) PublishDraft(id utils.ID) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	d := q.Draft(id)
+	if d == nil {
+		return fmt.Errorf("draft not found")
+	}
+	if d.Status != DraftStatusApproved {
+		return fmt.Errorf("draft not approved")
+	}
+	if err := q.checkValue(d.Value); err != nil {
+		return err
+	}
+	q.raise(DraftPublished{
+		Key:     q.Key,
+		DraftID: id,
+		Value:   d.Value,
+	})
+	return nil
+}
+
+func (q *Question) checkDraft(id utils.ID, reviewer string) (*Draft, error) {
+	if q.Deleted {
+		return nil, fmt.Errorf("question deleted")
+	}
+	if reviewer == "" {
+		return nil, fmt.Errorf("anonymous review")
+	}
+	d := q.Draft(id)
+	if d == nil {
+		return nil, fmt.Errorf("draft not found")
+	}
+	return d, nil
+}
+
+func (q *Question) onDraft(ev Event) {
+	switch e := ev.(type) {
+	case DraftCreated:
+		q.Drafts = append(q.Drafts, Draft{
+			ID:     e.DraftID,
+			Author: e.Author,
+			Value:  e.Value,
+			Status: DraftStatusProposed,
+		})
+	case DraftApproved:
+		if d := q.Draft(e.DraftID); d != nil {
+			d.Status = DraftStatusApproved
+			d.Reviewer = e.Reviewer
+			d.Comment = e.Comment
+		}
+	case DraftRejected:
+		q.removeDraft(e.DraftID)
+	case DraftPublished:
+		q.removeDraft(e.DraftID)
+		q.Value = e.Value
+		if a := q.Answer(q.Accepted); a != nil {
+			a.Value = e.Value
+		}
+	}
+}
+
+func (q *Question) removeDraft(id utils.ID) {
+	for i := range q.Drafts {
+		if bytes.Equal(q.Drafts[i].ID, id) {
+			q.Drafts = append(q.Drafts[:i:i], q.Drafts[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"answer.io/pkg/utils"
+)
+
+// Envelope wraps an event with the metadata needed to audit it.
+type Envelope struct {
+	ID          utils.ID  `json:"id"`
+	Sequence    uint64    `json:"sequence"`
+	Version     int       `json:"version"`
+	Timestamp   time.Time `json:"timestamp"`
+	Actor       string    `json:"actor"`
+	CausationID string    `json:"causation_id"`
+	Event       Event     `json:"-"`
+}
+
+// NewEnvelope wraps ev, produced at version of the aggregate. The actor and
+// causation ID are taken from ctx.
+func NewEnvelope(ctx context.Context, version int, ev Event) Envelope {
+	return Envelope{
+		ID:          utils.NextID(),
+		Version:     version,
+		Timestamp:   utils.Clock().UTC(),
+		Actor:       ActorFromContext(ctx),
+		CausationID: CausationIDFromContext(ctx),
+		Event:       ev,
+	}
+}
@@ -0,0 +1,13 @@
+package model
+
+import "fmt"
+
+// MovedError is returned when a question was renamed and is now stored at
+// Key.
+type MovedError struct {
+	Key Key
+}
+
+func (e *MovedError) Error() string {
+	return fmt.Sprintf("question moved to %q", e.Key)
+}
@@ -3,12 +3,40 @@ package model
 import (
 	"answer.io/pkg/utils"
 	"encoding/gob"
+	"strconv"
+	"time"
 )
 
 func init() {
 	gob.Register(QuestionAdded{})
 	gob.Register(QuestionUpdate{})
 	gob.Register(QuestionDelete{})
+	gob.Register(QuestionRestored{})
+	gob.Register(QuestionKeyChanged{})
+	gob.Register(AnswerAdded{})
+	gob.Register(AnswerEdited{})
+	gob.Register(AnswerRemoved{})
+	gob.Register(AnswerVoted{})
+	gob.Register(AnswerAccepted{})
+	gob.Register(QuestionTagged{})
+	gob.Register(QuestionUntagged{})
+	gob.Register(TranslationSet{})
+	gob.Register(TranslationRemoved{})
+	gob.Register(QuestionTemplated{})
+	gob.Register(AliasAdded{})
+	gob.Register(AliasRemoved{})
+	gob.Register(ValidityChanged{})
+	gob.Register(ValueScheduled{})
+	gob.Register(ScheduleCanceled{})
+	gob.Register(ScheduledValuePublished{})
+	gob.Register(ValidityStarted{})
+	gob.Register(ValidityEnded{})
+	gob.Register(DraftCreated{})
+	gob.Register(DraftApproved{})
+	gob.Register(DraftRejected{})
+	gob.Register(DraftPublished{})
+	gob.Register(AccessGranted{})
+	gob.Register(AccessRevoked{})
 }
 
 var _ Event = &QuestionAdded{}
@@ -53,3 +81,386 @@ func (q QuestionDelete) Data() Data {
 		Key: string(q.Key),
 	}
 }
+
+type QuestionRestored struct {
+	Key Key `json:"key"`
+}
+
+func (q QuestionRestored) IsEvent()       {}
+func (q QuestionRestored) String() string { return "restore" }
+func (q QuestionRestored) Data() Data {
+	return Data{
+		Key: string(q.Key),
+	}
+}
+
+type QuestionKeyChanged struct {
+	ID     utils.ID `json:"id"`
+	OldKey Key      `json:"old_key"`
+	NewKey Key      `json:"new_key"`
+}
+
+func (q QuestionKeyChanged) IsEvent()       {}
+func (q QuestionKeyChanged) String() string { return "rename" }
+func (q QuestionKeyChanged) Data() Data {
+	return Data{
+		Key:   string(q.NewKey),
+		Value: string(q.OldKey),
+	}
+}
+
+type AnswerAdded struct {
+	Key      Key      `json:"key"`
+	AnswerID utils.ID `json:"answer_id"`
+	Author   string   `json:"author"`
+	Value    Value    `json:"value"`
+}
+
+func (q AnswerAdded) IsEvent()       {}
+func (q AnswerAdded) String() string { return "answer_add" }
+func (q AnswerAdded) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type AnswerEdited struct {
+	Key      Key      `json:"key"`
+	AnswerID utils.ID `json:"answer_id"`
+	Value    Value    `json:"value"`
+}
+
+func (q AnswerEdited) IsEvent()       {}
+func (q AnswerEdited) String() string { return "answer_edit" }
+func (q AnswerEdited) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type AnswerRemoved struct {
+	Key      Key      `json:"key"`
+	AnswerID utils.ID `json:"answer_id"`
+}
+
+func (q AnswerRemoved) IsEvent()       {}
+func (q AnswerRemoved) String() string { return "answer_remove" }
+func (q AnswerRemoved) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.AnswerID.String(),
+	}
+}
+
+type AnswerVoted struct {
+	Key      Key      `json:"key"`
+	AnswerID utils.ID `json:"answer_id"`
+	Voter    string   `json:"voter"`
+	Vote     int      `json:"vote"`
+}
+
+func (q AnswerVoted) IsEvent()       {}
+func (q AnswerVoted) String() string { return "answer_vote" }
+func (q AnswerVoted) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.AnswerID.String(),
+	}
+}
+
+type AnswerAccepted struct {
+	Key      Key      `json:"key"`
+	AnswerID utils.ID `json:"answer_id"`
+}
+
+func (q AnswerAccepted) IsEvent()       {}
+func (q AnswerAccepted) String() string { return "answer_accept" }
+func (q AnswerAccepted) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.AnswerID.String(),
+	}
+}
+
+type QuestionTagged struct {
+	Key Key    `json:"key"`
+	Tag string `json:"tag"`
+}
+
+func (q QuestionTagged) IsEvent()       {}
+func (q QuestionTagged) String() string { return "tag" }
+func (q QuestionTagged) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Tag,
+	}
+}
+
+type QuestionUntagged struct {
+	Key Key    `json:"key"`
+	Tag string `json:"tag"`
+}
+
+func (q QuestionUntagged) IsEvent()       {}
+func (q QuestionUntagged) String() string { return "untag" }
+func (q QuestionUntagged) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Tag,
+	}
+}
+
+type TranslationSet struct {
+	Key    Key    `json:"key"`
+	Locale string `json:"locale"`
+	Value  Value  `json:"value"`
+	// SourceVersion is the version of the question translated.
+	SourceVersion int `json:"source_version"`
+}
+
+func (q TranslationSet) IsEvent()       {}
+func (q TranslationSet) String() string { return "translate" }
+func (q TranslationSet) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type TranslationRemoved struct {
+	Key    Key    `json:"key"`
+	Locale string `json:"locale"`
+}
+
+func (q TranslationRemoved) IsEvent()       {}
+func (q TranslationRemoved) String() string { return "untranslate" }
+func (q TranslationRemoved) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Locale,
+	}
+}
+
+type QuestionTemplated struct {
+	Key      Key  `json:"key"`
+	Template bool `json:"template"`
+}
+
+func (q QuestionTemplated) IsEvent()       {}
+func (q QuestionTemplated) String() string { return "template" }
+func (q QuestionTemplated) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: strconv.FormatBool(q.Template),
+	}
+}
+
+type AliasAdded struct {
+	Key   Key `json:"key"`
+	Alias Key `json:"alias"`
+}
+
+func (q AliasAdded) IsEvent()       {}
+func (q AliasAdded) String() string { return "alias" }
+func (q AliasAdded) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Alias),
+	}
+}
+
+type AliasRemoved struct {
+	Key   Key `json:"key"`
+	Alias Key `json:"alias"`
+}
+
+func (q AliasRemoved) IsEvent()       {}
+func (q AliasRemoved) String() string { return "unalias" }
+func (q AliasRemoved) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Alias),
+	}
+}
+
+type ValidityChanged struct {
+	Key   Key       `json:"key"`
+	From  time.Time `json:"from"`
+	Until time.Time `json:"until"`
+}
+
+func (q ValidityChanged) IsEvent()       {}
+func (q ValidityChanged) String() string { return "validity" }
+func (q ValidityChanged) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: formatTime(q.From) + "/" + formatTime(q.Until),
+	}
+}
+
+type ValueScheduled struct {
+	Key   Key       `json:"key"`
+	Value Value     `json:"value"`
+	At    time.Time `json:"at"`
+}
+
+func (q ValueScheduled) IsEvent()       {}
+func (q ValueScheduled) String() string { return "schedule" }
+func (q ValueScheduled) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type ScheduleCanceled struct {
+	Key Key `json:"key"`
+}
+
+func (q ScheduleCanceled) IsEvent()       {}
+func (q ScheduleCanceled) String() string { return "unschedule" }
+func (q ScheduleCanceled) Data() Data {
+	return Data{
+		Key: string(q.Key),
+	}
+}
+
+type ScheduledValuePublished struct {
+	Key   Key   `json:"key"`
+	Value Value `json:"value"`
+}
+
+func (q ScheduledValuePublished) IsEvent()       {}
+func (q ScheduledValuePublished) String() string { return "scheduled_update" }
+func (q ScheduledValuePublished) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type ValidityStarted struct {
+	Key Key `json:"key"`
+}
+
+func (q ValidityStarted) IsEvent()       {}
+func (q ValidityStarted) String() string { return "validity_start" }
+func (q ValidityStarted) Data() Data {
+	return Data{
+		Key: string(q.Key),
+	}
+}
+
+type ValidityEnded struct {
+	Key Key `json:"key"`
+}
+
+func (q ValidityEnded) IsEvent()       {}
+func (q ValidityEnded) String() string { return "validity_end" }
+func (q ValidityEnded) Data() Data {
+	return Data{
+		Key: string(q.Key),
+	}
+}
+
+type DraftCreated struct {
+	Key     Key      `json:"key"`
+	DraftID utils.ID `json:"draft_id"`
+	Author  string   `json:"author"`
+	Value   Value    `json:"value"`
+}
+
+func (q DraftCreated) IsEvent()       {}
+func (q DraftCreated) String() string { return "draft_propose" }
+func (q DraftCreated) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type DraftApproved struct {
+	Key      Key      `json:"key"`
+	DraftID  utils.ID `json:"draft_id"`
+	Reviewer string   `json:"reviewer"`
+	Comment  string   `json:"comment"`
+}
+
+func (q DraftApproved) IsEvent()       {}
+func (q DraftApproved) String() string { return "draft_approve" }
+func (q DraftApproved) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Comment,
+	}
+}
+
+type DraftRejected struct {
+	Key      Key      `json:"key"`
+	DraftID  utils.ID `json:"draft_id"`
+	Reviewer string   `json:"reviewer"`
+	Comment  string   `json:"comment"`
+}
+
+func (q DraftRejected) IsEvent()       {}
+func (q DraftRejected) String() string { return "draft_reject" }
+func (q DraftRejected) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Comment,
+	}
+}
+
+type DraftPublished struct {
+	Key     Key      `json:"key"`
+	DraftID utils.ID `json:"draft_id"`
+	Value   Value    `json:"value"`
+}
+
+func (q DraftPublished) IsEvent()       {}
+func (q DraftPublished) String() string { return "draft_publish" }
+func (q DraftPublished) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: string(q.Value),
+	}
+}
+
+type AccessGranted struct {
+	Key        Key        `json:"key"`
+	Subject    string     `json:"subject"`
+	Permission Permission `json:"permission"`
+}
+
+func (q AccessGranted) IsEvent()       {}
+func (q AccessGranted) String() string { return "acl_grant" }
+func (q AccessGranted) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Subject + "=" + string(q.Permission),
+	}
+}
+
+type AccessRevoked struct {
+	Key     Key    `json:"key"`
+	Subject string `json:"subject"`
+}
+
+func (q AccessRevoked) IsEvent()       {}
+func (q AccessRevoked) String() string { return "acl_revoke" }
+func (q AccessRevoked) Data() Data {
+	return Data{
+		Key:   string(q.Key),
+		Value: q.Subject,
+	}
+}
+
+// formatTime returns t in RFC 3339, or an empty string when it is zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,22 @@
+package model
+
+// ListOptions selects a page of questions ordered by key.
+type ListOptions struct {
+	// Limit is the maximum number of questions of the page, zero means no
+	// limit.
+	Limit int
+	// Token continues the listing after the page that returned it.
+	Token string
+	// Prefix keeps the keys starting with it.
+	Prefix Key
+	// Start is the first key of the range, inclusive.
+	Start Key
+	// End is the last key of the range, exclusive.
+	End Key
+	// Reverse lists the keys in descending order.
+	Reverse bool
+	// Tags keeps the questions with every one of them, or with any of them
+	// when AnyTag is set.
+	Tags   []string
+	AnyTag bool
+}
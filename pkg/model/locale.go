@@ -0,0 +1,144 @@
+package model
+
+import (
+	"fmt"
+
+	"answer.io/pkg/text"
+
+	"golang.org/x/text/language"
+)
+
+// Translation is the value of a question in a locale.
+type Translation struct {
+	Value Value `json:"value"`
+	// SourceVersion is the version of the question translated.
+	SourceVersion int `json:"source_version"`
+	// Outdated tells that the default value changed since SourceVersion.
+	Outdated bool `json:"outdated"`
+}
+
+// OutdatedTranslation is a translation made from a default value changed
+// since.
+type OutdatedTranslation struct {
+	Key    Key    `json:"key"`
+	Locale string `json:"locale"`
+	Translation
+	// Source is the current default value.
+	Source Value `json:"source"`
+	// Diff are the edits of the default value since the translation.
+	Diff []text.Edit `json:"diff"`
+}
+
+// MissingTranslation lists the locales a question isn't translated to.
+type MissingTranslation struct {
+	Key     Key      `json:"key"`
+	Locales []string `json:"locales"`
+}
+
+// ParseLocale returns the canonical form of a BCP 47 locale.
+func ParseLocale(s string) (string, error) {
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid locale %q: %w", s, err)
+	}
+	if tag == language.Und {
+		return "", fmt.Errorf("undefined locale")
+	}
+	return tag.String(), nil
+}
+
+// Translate sets the value of q in locale.
+func (q *Question) Translate(locale string, value Value) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	locale, err := ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+	if err := q.checkValue(value); err != nil {
+		return err
+	}
+	if t, ok := q.Translations[locale]; ok && t.Value == value && !t.Outdated {
+		return nil
+	}
+	q.raise(TranslationSet{
+		Key:           q.Key,
+		Locale:        locale,
+		Value:         value,
+		SourceVersion: q.Version,
+	})
+	return nil
+}
+
+func (q *Question) RemoveTranslation(locale string) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	locale, err := ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+	if _, ok := q.Translations[locale]; !ok {
+		return fmt.Errorf("translation %q not found", locale)
+	}
+	q.raise(TranslationRemoved{Key: q.Key, Locale: locale})
+	return nil
+}
+
+// Localize returns the value of q in the first of the preferred locales it
+// is translated to, falling back from each locale to its parents: es-MX,
+// es-419, es. A preferred locale matching def, the locale of the default
+// value, or one of its parents, serves the default value, even when a
+// translation is stored for it. The locale is def when the default value is
+// returned.
+func (q *Question) Localize(prefs []language.Tag, def language.Tag) (Value, language.Tag) {
+	for _, tag := range prefs {
+		for ; tag != language.Und; tag = tag.Parent() {
+			if isParent(tag, def) {
+				return q.Value, def
+			}
+			if t, ok := q.Translations[tag.String()]; ok {
+				return t.Value, tag
+			}
+		}
+	}
+	return q.Value, def
+}
+
+// isParent tells whether parent is tag or one of its parents.
+func isParent(parent, tag language.Tag) bool {
+	for ; tag != language.Und; tag = tag.Parent() {
+		if tag == parent {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Question) onTranslation(ev Event) {
+	switch e := ev.(type) {
+	case TranslationSet:
+		if q.Translations == nil {
+			q.Translations = map[string]Translation{}
+		}
+		q.Translations[e.Locale] = Translation{
+			Value:         e.Value,
+			SourceVersion: e.SourceVersion,
+		}
+	case TranslationRemoved:
+		delete(q.Translations, e.Locale)
+		if len(q.Translations) == 0 {
+			q.Translations = nil
+		}
+	}
+}
+
+// outdateTranslations flags the translations of q as outdated, after a
+// change of its default value.
+func (q *Question) outdateTranslations() {
+	for locale, t := range q.Translations {
+		t.Outdated = true
+		q.Translations[locale] = t
+	}
+}
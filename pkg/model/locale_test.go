@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestQuestionLocalize(t *testing.T) {
+	q := Question{
+		Value: "Refunds take 5 days",
+		Translations: map[string]Translation{
+			"es":    {Value: "Los reembolsos tardan 5 días"},
+			"fr-CA": {Value: "Les remboursements prennent 5 jours"},
+			"en":    {Value: "Refunds take a week"},
+		},
+	}
+	var testCases = []struct {
+		name       string
+		prefs      string
+		def        language.Tag
+		want       Value
+		wantLocale language.Tag
+	}{
+		{
+			name:       "fallback to the language",
+			prefs:      "es-MX",
+			want:       "Los reembolsos tardan 5 días",
+			wantLocale: language.Spanish,
+		},
+		{
+			name:       "second preference",
+			prefs:      "de, fr-CA;q=0.8",
+			want:       "Les remboursements prennent 5 jours",
+			wantLocale: language.CanadianFrench,
+		},
+		{
+			name:       "no fallback to a sibling region",
+			prefs:      "fr-FR",
+			want:       "Refunds take 5 days",
+			wantLocale: language.Und,
+		},
+		{
+			name:       "default locale preferred",
+			prefs:      "en, es;q=0.5",
+			def:        language.English,
+			want:       "Refunds take 5 days",
+			wantLocale: language.English,
+		},
+		{
+			name:       "default locale of a region",
+			prefs:      "en-GB, es;q=0.5",
+			def:        language.English,
+			want:       "Refunds take 5 days",
+			wantLocale: language.English,
+		},
+		{
+			name:       "parent of the default locale",
+			prefs:      "en, es;q=0.5",
+			def:        language.AmericanEnglish,
+			want:       "Refunds take 5 days",
+			wantLocale: language.AmericanEnglish,
+		},
+		{
+			name:       "translation in the default locale",
+			prefs:      "en-GB",
+			def:        language.English,
+			want:       "Refunds take 5 days",
+			wantLocale: language.English,
+		},
+		{
+			name:       "default value",
+			prefs:      "",
+			want:       "Refunds take 5 days",
+			wantLocale: language.Und,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs, _, err := language.ParseAcceptLanguage(tt.prefs)
+			if err != nil {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			got, locale := q.Localize(prefs, tt.def)
+			if got != tt.want || locale != tt.wantLocale {
+				t.Errorf("got = %q in %s, want %q in %s", got, locale, tt.want, tt.wantLocale)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Miss is a key looked up, or a text searched, without finding a question.
+type Miss struct {
+	Key       Key       `json:"key"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// Samples are the sources of the latest misses.
+	Samples []string `json:"samples"`
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"answer.io/pkg/utils"
 )
@@ -10,12 +11,34 @@ type Key string
 type Value string
 
 type Question struct {
-	Id      utils.ID `json:"id"`
-	Key     Key      `json:"key"`
-	Value   Value    `json:"value"`
-	Deleted bool     `json:"deleted"`
-	History []Event  `json:"history"`
-	Version int      `json:"version"`
+	Id       utils.ID `json:"id"`
+	Key      Key      `json:"key"`
+	Value    Value    `json:"value"`
+	Deleted  bool     `json:"deleted"`
+	History  []Event  `json:"history"`
+	Version  int      `json:"version"`
+	Answers  []Answer `json:"answers"`
+	Accepted utils.ID `json:"accepted"`
+	Tags     []string `json:"tags"`
+	// Translations holds the value of the question per BCP 47 locale.
+	Translations map[string]Translation `json:"translations"`
+	// Template tells that the values are text/template templates.
+	Template bool `json:"template"`
+	// Aliases are other keys of the question.
+	Aliases []Key `json:"aliases"`
+	// ValidFrom and ValidUntil bound the time the question is visible, they
+	// are zero when unbounded.
+	ValidFrom  time.Time `json:"valid_from"`
+	ValidUntil time.Time `json:"valid_until"`
+	// Started and Ended tell that the scheduler saw ValidFrom and
+	// ValidUntil pass.
+	Started   bool            `json:"started"`
+	Ended     bool            `json:"ended"`
+	Scheduled *ScheduledValue `json:"scheduled"`
+	// Drafts are the changes of the value waiting to be published.
+	Drafts []Draft `json:"drafts"`
+	// ACL restricts the access to the question when it isn't empty.
+	ACL []Grant `json:"acl"`
 }
 
 func NewFromEvents(events []Event) *Question {
@@ -48,6 +71,9 @@ func (q *Question) Update(value Value) error {
 	if q.Deleted {
 		return fmt.Errorf("question deleted")
 	}
+	if err := q.checkValue(value); err != nil {
+		return err
+	}
 
 	q.raise(QuestionUpdate{
 		Key:      q.Key,
@@ -67,18 +93,95 @@ func (q *Question) Delete() error {
 	return nil
 }
 
+func (q *Question) Restore() error {
+	if !q.Deleted {
+		return fmt.Errorf("question not deleted")
+	}
+	q.Deleted = false
+	q.raise(QuestionRestored{Key: q.Key})
+	return nil
+}
+
+// Recreate continues the log of the deleted question q with the events of
+// n, a question created again at its key.
+func (q *Question) Recreate(n *Question) error {
+	if !q.Deleted {
+		return fmt.Errorf("question not deleted")
+	}
+	for _, ev := range n.Events() {
+		q.History = append(q.History, ev)
+		q.On(ev, false)
+	}
+	return nil
+}
+
+func (q *Question) Rename(key Key) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if key == "" || key == q.Key {
+		return fmt.Errorf("invalid key %q", key)
+	}
+	q.raise(QuestionKeyChanged{
+		ID:     q.Id,
+		OldKey: q.Key,
+		NewKey: key,
+	})
+	return nil
+}
+
 func (q *Question) On(ev Event, new bool) {
+	value := q.Value
 	switch e := ev.(type) {
 	case QuestionAdded:
+		// A question created again after a delete continues its log from
+		// scratch, under the same ACL.
+		*q = Question{History: q.History, Version: q.Version, ACL: q.ACL}
 		q.Id = e.ID
 		q.Key = e.Key
 		q.Value = e.Value
 	case QuestionUpdate:
 		q.Value = e.NewValue
+		if a := q.Answer(q.Accepted); a != nil {
+			a.Value = e.NewValue
+		}
 		new = false
 	case QuestionDelete:
 		q.Deleted = true
 		new = false
+	case QuestionRestored:
+		q.Deleted = false
+		new = false
+	case QuestionKeyChanged:
+		q.Key = e.NewKey
+		new = false
+	case AnswerAdded, AnswerEdited, AnswerRemoved, AnswerVoted, AnswerAccepted:
+		q.onAnswer(e)
+		new = false
+	case QuestionTagged, QuestionUntagged:
+		q.onTag(e)
+		new = false
+	case TranslationSet, TranslationRemoved:
+		q.onTranslation(e)
+		new = false
+	case QuestionTemplated:
+		q.Template = e.Template
+		new = false
+	case AliasAdded, AliasRemoved:
+		q.onAlias(e)
+		new = false
+	case ValidityChanged, ValueScheduled, ScheduleCanceled, ScheduledValuePublished, ValidityStarted, ValidityEnded:
+		q.onSchedule(e)
+		new = false
+	case DraftCreated, DraftApproved, DraftRejected, DraftPublished:
+		q.onDraft(e)
+		new = false
+	case AccessGranted, AccessRevoked:
+		q.onACL(e)
+		new = false
+	}
+	if q.Value != value {
+		q.outdateTranslations()
 	}
 	if !new {
 		q.Version++
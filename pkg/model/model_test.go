@@ -83,20 +83,20 @@ func TestNewFromEvents(t *testing.T) {
 		{
 			name: "create questions from event",
 			in: []Event{
-				&QuestionAdded{
+				QuestionAdded{
 					ID:    utils.NextID(),
 					Key:   Key("new_key"),
 					Value: Value("new value"),
 				},
-				&QuestionUpdate{
+				QuestionUpdate{
 					Key:      Key("new_key"),
 					NewValue: Value("new value"),
 				},
-				&QuestionUpdate{
+				QuestionUpdate{
 					Key:      Key("new_key"),
 					NewValue: Value("other value"),
 				},
-				&QuestionDelete{
+				QuestionDelete{
 					Key: Key("new_key"),
 				},
 			},
@@ -107,20 +107,20 @@ func TestNewFromEvents(t *testing.T) {
 				Version: 4,
 				Deleted: true,
 				History: []Event{
-					&QuestionAdded{
+					QuestionAdded{
 						ID:    utils.NextID(),
 						Key:   Key("new_key"),
 						Value: Value("new value"),
 					},
-					&QuestionUpdate{
+					QuestionUpdate{
 						Key:      Key("new_key"),
 						NewValue: Value("new value"),
 					},
-					&QuestionUpdate{
+					QuestionUpdate{
 						Key:      Key("new_key"),
 						NewValue: Value("other value"),
 					},
-					&QuestionDelete{
+					QuestionDelete{
 						Key: Key("new_key"),
 					},
 				},
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+)
+
+// referencePattern matches the references to other questions inside a
+// value: [[key]].
+var referencePattern = regexp.MustCompile(`\[\[([^\[\]\s]+)\]\]`)
+
+// References returns the keys referenced by value, sorted and distinct.
+func References(value Value) []Key {
+	seen := map[Key]bool{}
+	var keys []Key
+	for _, m := range referencePattern.FindAllStringSubmatch(string(value), -1) {
+		key := Key(m[1])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// References returns the keys referenced by the value of q and by its
+// translations.
+func (q *Question) References() []Key {
+	value := q.Value
+	for _, t := range q.Translations {
+		value += "\n" + t.Value
+	}
+	return References(value)
+}
+
+// Transclude replaces the references inside value with what include
+// returns for their key. A reference is kept as is when include returns
+// false.
+func Transclude(value Value, include func(key Key) (Value, bool)) Value {
+	return Value(referencePattern.ReplaceAllStringFunc(string(value), func(ref string) string {
+		key := Key(referencePattern.FindStringSubmatch(ref)[1])
+		if v, ok := include(key); ok {
+			return string(v)
+		}
+		return ref
+	}))
+}
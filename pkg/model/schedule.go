@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScheduledValue is a value replacing the one of a question at a given time.
+type ScheduledValue struct {
+	Value Value     `json:"value"`
+	At    time.Time `json:"at"`
+}
+
+// SetValidity sets the window of time q is visible in, a zero time leaves
+// its side of the window open.
+func (q *Question) SetValidity(from, until time.Time) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if !from.IsZero() && !until.IsZero() && !until.After(from) {
+		return fmt.Errorf("valid until %s not after valid from %s", until.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+	if from.Equal(q.ValidFrom) && until.Equal(q.ValidUntil) {
+		return nil
+	}
+	q.raise(ValidityChanged{Key: q.Key, From: from, Until: until})
+	return nil
+}
+
+// ScheduleValue replaces the value of q with value at time at.
+func (q *Question) ScheduleValue(value Value, at time.Time) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if at.IsZero() {
+		return fmt.Errorf("missing time of the scheduled value")
+	}
+	if err := q.checkValue(value); err != nil {
+		return err
+	}
+	q.raise(ValueScheduled{Key: q.Key, Value: value, At: at})
+	return nil
+}
+
+func (q *Question) CancelSchedule() error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if q.Scheduled == nil {
+		return fmt.Errorf("no scheduled value")
+	}
+	q.raise(ScheduleCanceled{Key: q.Key})
+	return nil
+}
+
+// Visible reports whether now is inside the validity window of q.
+func (q *Question) Visible(now time.Time) bool {
+	if !q.ValidFrom.IsZero() && now.Before(q.ValidFrom) {
+		return false
+	}
+	return q.ValidUntil.IsZero() || now.Before(q.ValidUntil)
+}
+
+// Pending reports whether q waits for a scheduled value or for a side of
+// its validity window to pass.
+func (q *Question) Pending() bool {
+	return q.Scheduled != nil ||
+		(!q.ValidFrom.IsZero() && !q.Started) ||
+		(!q.ValidUntil.IsZero() && !q.Ended)
+}
+
+// Effective returns q as it is at time now, with the scheduled value in place
+// when its time passed.
+func (q Question) Effective(now time.Time) Question {
+	if q.Scheduled != nil && !now.Before(q.Scheduled.At) {
+		q.Value = q.Scheduled.Value
+		q.Scheduled = nil
+	}
+	return q
+}
+
+// Tick raises the events of the scheduled value and of the sides of the
+// validity window of q passed at time now.
+func (q *Question) Tick(now time.Time) {
+	if q.Deleted {
+		return
+	}
+	if q.Scheduled != nil && !now.Before(q.Scheduled.At) {
+		q.raise(ScheduledValuePublished{Key: q.Key, Value: q.Scheduled.Value})
+	}
+	if !q.ValidFrom.IsZero() && !q.Started && !now.Before(q.ValidFrom) {
+		q.raise(ValidityStarted{Key: q.Key})
+	}
+	if !q.ValidUntil.IsZero() && !q.Ended && !now.Before(q.ValidUntil) {
+		q.raise(ValidityEnded{Key: q.Key})
+	}
+}
+
+func (q *Question) onSchedule(ev Event) {
+	switch e := ev.(type) {
+	case ValidityChanged:
+		q.ValidFrom = e.From
+		q.ValidUntil = e.Until
+		q.Started = false
+		q.Ended = false
+	case ValueScheduled:
+		q.Scheduled = &ScheduledValue{Value: e.Value, At: e.At}
+	case ScheduleCanceled:
+		q.Scheduled = nil
+	case ScheduledValuePublished:
+		q.Value = e.Value
+		q.Scheduled = nil
+		if a := q.Answer(q.Accepted); a != nil {
+			a.Value = e.Value
+		}
+	case ValidityStarted:
+		q.Started = true
+	case ValidityEnded:
+		q.Ended = true
+	}
+}
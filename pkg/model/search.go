@@ -0,0 +1,16 @@
+package model
+
+// SearchResult is a question matching a search, with the relevance of the
+// match and an extract of the question showing it.
+type SearchResult struct {
+	Question Question
+	Score    float64
+	Snippet  string
+}
+
+// Match is a question answering a text, with the confidence of the match
+// between 0 and 1.
+type Match struct {
+	Question   Question
+	Confidence float64
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagCount is the number of questions with a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag returns tag trimmed and in lower case, or an error when it is
+// not a valid tag.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("empty tag")
+	}
+	if strings.ContainsAny(tag, ",/\x00") {
+		return "", fmt.Errorf("invalid tag %q", tag)
+	}
+	return tag, nil
+}
+
+// HasTag reports whether q is tagged with tag.
+func (q *Question) HasTag(tag string) bool {
+	i := sort.SearchStrings(q.Tags, tag)
+	return i < len(q.Tags) && q.Tags[i] == tag
+}
+
+// Tag adds tag to q, it does nothing when q already has it.
+func (q *Question) Tag(tag string) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	if q.HasTag(tag) {
+		return nil
+	}
+	q.raise(QuestionTagged{Key: q.Key, Tag: tag})
+	return nil
+}
+
+func (q *Question) Untag(tag string) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	if !q.HasTag(tag) {
+		return fmt.Errorf("tag %q not found", tag)
+	}
+	q.raise(QuestionUntagged{Key: q.Key, Tag: tag})
+	return nil
+}
+
+func (q *Question) onTag(ev Event) {
+	switch e := ev.(type) {
+	case QuestionTagged:
+		if q.HasTag(e.Tag) {
+			return
+		}
+		i := sort.SearchStrings(q.Tags, e.Tag)
+		q.Tags = append(q.Tags, "")
+		copy(q.Tags[i+1:], q.Tags[i:])
+		q.Tags[i] = e.Tag
+	case QuestionUntagged:
+		i := sort.SearchStrings(q.Tags, e.Tag)
+		if i < len(q.Tags) && q.Tags[i] == e.Tag {
+			q.Tags = append(q.Tags[:i:i], q.Tags[i+1:]...)
+		}
+	}
+}
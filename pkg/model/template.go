@@ -0,0 +1,132 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// dateLayouts are the layouts of the dates given to the date function of
+// the templates.
+var dateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// templateFuncs are the functions available to the templates. They only
+// work on their arguments.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// date formats a RFC 3339 date, or a YYYY-MM-DD one, with a Go layout.
+	"date": func(layout, s string) (string, error) {
+		for _, l := range dateLayouts {
+			if t, err := time.Parse(l, s); err == nil {
+				return t.Format(layout), nil
+			}
+		}
+		return "", fmt.Errorf("invalid date %q", s)
+	},
+	// plural returns singular when n is 1, plural otherwise.
+	"plural": func(n interface{}, singular, plural string) (string, error) {
+		var count int
+		switch v := n.(type) {
+		case int:
+			count = v
+		case string:
+			var err error
+			if count, err = strconv.Atoi(v); err != nil {
+				return "", fmt.Errorf("invalid count %q", v)
+			}
+		default:
+			return "", fmt.Errorf("invalid count %v", n)
+		}
+		if count == 1 {
+			return singular, nil
+		}
+		return plural, nil
+	},
+	// default returns value, or def when value is empty.
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+}
+
+// ParseTemplate parses value as a text/template.
+func ParseTemplate(value Value) (*template.Template, error) {
+	t, err := template.New("value").Funcs(templateFuncs).Option("missingkey=zero").Parse(string(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// Render executes value as a template with vars, the variables are fields
+// of the dot: {{.name}}.
+func Render(value Value, vars map[string]string) (Value, error) {
+	t, err := ParseTemplate(value)
+	if err != nil {
+		return "", err
+	}
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return Value(b.String()), nil
+}
+
+// Literal returns a template rendering value as is, whatever the actions
+// it contains.
+func Literal(value Value) Value {
+	if value == "" {
+		return ""
+	}
+	return Value("{{" + strconv.Quote(string(value)) + "}}")
+}
+
+// SetTemplate marks the value of q, and of its translations, as a template
+// or as plain text.
+func (q *Question) SetTemplate(on bool) error {
+	if q.Deleted {
+		return fmt.Errorf("question deleted")
+	}
+	if q.Template == on {
+		return nil
+	}
+	if on {
+		if err := q.checkTemplates(); err != nil {
+			return err
+		}
+	}
+	q.raise(QuestionTemplated{Key: q.Key, Template: on})
+	return nil
+}
+
+// checkTemplates returns an error when the value of q or one of its
+// translations is not a valid template.
+func (q *Question) checkTemplates() error {
+	if _, err := ParseTemplate(q.Value); err != nil {
+		return err
+	}
+	for locale, t := range q.Translations {
+		if _, err := ParseTemplate(t.Value); err != nil {
+			return fmt.Errorf("%s translation: %w", locale, err)
+		}
+	}
+	return nil
+}
+
+// checkValue returns an error when q is a template and value is not a valid
+// one.
+func (q *Question) checkValue(value Value) error {
+	if !q.Template {
+		return nil
+	}
+	_, err := ParseTemplate(value)
+	return err
+}
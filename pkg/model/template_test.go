@@ -0,0 +1,70 @@
+package model
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		value   Value
+		vars    map[string]string
+		want    Value
+		wantErr bool
+	}{
+		{
+			name:  "variables",
+			value: "{{.product}} costs {{.price}}",
+			vars:  map[string]string{"product": "Pro", "price": "10€"},
+			want:  "Pro costs 10€",
+		},
+		{
+			name:  "missing variable with default",
+			value: "Hello {{default \"there\" .name}}",
+			want:  "Hello there",
+		},
+		{
+			name:  "date",
+			value: "Open on {{date \"Jan 2\" .day}}",
+			vars:  map[string]string{"day": "2022-05-01"},
+			want:  "Open on May 1",
+		},
+		{
+			name:  "plural",
+			value: "{{.n}} {{plural .n \"day\" \"days\"}}, 1 {{plural 1 \"day\" \"days\"}}",
+			vars:  map[string]string{"n": "5"},
+			want:  "5 days, 1 day",
+		},
+		{
+			name:  "literal",
+			value: "Hi {{.name}}, " + Literal("use {{.name}} and \"}}\"\n"),
+			vars:  map[string]string{"name": "Jane"},
+			want:  "Hi Jane, use {{.name}} and \"}}\"\n",
+		},
+		{
+			name:    "invalid date",
+			value:   "{{date \"Jan 2\" .day}}",
+			vars:    map[string]string{"day": "tomorrow"},
+			wantErr: true,
+		},
+		{
+			name:    "malformed template",
+			value:   "{{.name",
+			wantErr: true,
+		},
+		{
+			name:    "unknown function",
+			value:   "{{env \"HOME\"}}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.value, tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
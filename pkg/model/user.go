@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// User is a principal authenticated with a password.
+type User struct {
+	Name   string   `json:"name"`
+	Role   Role     `json:"role"`
+	Groups []string `json:"groups,omitempty"`
+	// Tenant is the tenant the user is bound to, or AllTenants.
+	Tenant    string    `json:"tenant"`
+	CreatedAt time.Time `json:"created_at"`
+	// PasswordChangedAt is when the password was set.
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	// PasswordGeneration counts the changes of the password, the tokens
+	// issued for another generation are no longer valid.
+	PasswordGeneration int `json:"-"`
+}
+
+// Principal returns the principal u is authenticated as.
+func (u User) Principal() Principal {
+	return Principal{Name: u.Name, Role: u.Role, Groups: u.Groups, Tenant: u.Tenant}
+}
+
+// Group is a set of users the grants of the ACLs can name.
+type Group struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
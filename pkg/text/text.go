@@ -0,0 +1,126 @@
+// Package text normalizes and tokenizes the keys and values of questions.
+package text
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize returns s in lower case and without diacritics.
+func Normalize(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	r, _, err := transform.String(t, s)
+	if err != nil {
+		r = s
+	}
+	return strings.ToLower(r)
+}
+
+// Tokenize returns the normalized words of s. Words are made of letters and
+// digits, anything else, like the separators of keys, splits them.
+func Tokenize(s string) []string {
+	var tokens []string
+	for _, w := range words(s) {
+		tokens = append(tokens, Normalize(s[w.start:w.end]))
+	}
+	return tokens
+}
+
+// Highlight returns a window of s of at most size words around the first
+// word matching one of terms, which must be normalized. Every word of the
+// window matching terms is wrapped in a mark tag, the rest of the text is
+// escaped as HTML.
+func Highlight(s string, terms []string, size int) string {
+	match := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		match[t] = true
+	}
+	ws := words(s)
+	if len(ws) == 0 || size <= 0 {
+		return ""
+	}
+	first := 0
+	for i, w := range ws {
+		if match[Normalize(s[w.start:w.end])] {
+			first = i
+			break
+		}
+	}
+	from := first - size/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + size
+	if to > len(ws) {
+		to = len(ws)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("… ")
+	}
+	pos := ws[from].start
+	for _, w := range ws[from:to] {
+		b.WriteString(html.EscapeString(s[pos:w.start]))
+		if match[Normalize(s[w.start:w.end])] {
+			b.WriteString("<mark>" + html.EscapeString(s[w.start:w.end]) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(s[w.start:w.end]))
+		}
+		pos = w.end
+	}
+	if to < len(ws) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+// word is the byte range of a word in a string.
+type word struct {
+	start, end int
+}
+
+func words(s string) []word {
+	var (
+		ws    []word
+		start = -1
+	)
+	for i, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			ws = append(ws, word{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		ws = append(ws, word{start, len(s)})
+	}
+	return ws
+}
+
+// Trigrams returns the distinct trigrams of the words of s, sorted. Words
+// are normalized and padded so their first and last letters weight more.
+func Trigrams(s string) []string {
+	set := map[string]bool{}
+	for _, t := range Tokenize(s) {
+		r := []rune("  " + t + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			set[string(r[i:i+3])] = true
+		}
+	}
+	l := make([]string, 0, len(set))
+	for t := range set {
+		l = append(l, t)
+	}
+	sort.Strings(l)
+	return l
+}
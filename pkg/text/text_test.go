@@ -0,0 +1,148 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenize(t *testing.T) {
+	var testCases = []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "key separators",
+			in:   "refund-policy_EU",
+			want: []string{"refund", "policy", "eu"},
+		},
+		{
+			name: "diacritics and punctuation",
+			in:   "¿Cuál es la política?",
+			want: []string{"cual", "es", "la", "politica"},
+		},
+		{
+			name: "empty",
+			in:   " ,. ",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, Tokenize(tt.in)); diff != "" {
+				t.Errorf("unexpected tokens mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		in    string
+		terms []string
+		size  int
+		want  string
+	}{
+		{
+			name:  "whole text",
+			in:    "Refunds take 5 days.",
+			terms: []string{"refunds", "days"},
+			size:  10,
+			want:  "<mark>Refunds</mark> take 5 <mark>days</mark>",
+		},
+		{
+			name:  "window around the first match",
+			in:    "one two three Política five six seven",
+			terms: []string{"politica"},
+			size:  3,
+			want:  "… three <mark>Política</mark> five …",
+		},
+		{
+			name:  "escaped markup",
+			in:    "Refunds <script>alert(1)</script> & days",
+			terms: []string{"refunds"},
+			size:  10,
+			want:  "<mark>Refunds</mark> &lt;script&gt;alert(1)&lt;/script&gt; &amp; days",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, Highlight(tt.in, tt.terms, tt.size)); diff != "" {
+				t.Errorf("unexpected snippet mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	var testCases = []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "padded words",
+			in:   "Cat-Ox",
+			want: []string{"  c", "  o", " ca", " ox", "at ", "cat", "ox "},
+		},
+		{
+			name: "distinct trigrams",
+			in:   "aaa aaa",
+			want: []string{"  a", " aa", "aa ", "aaa"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, Trigrams(tt.in)); diff != "" {
+				t.Errorf("unexpected trigrams mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var testCases = []struct {
+		name string
+		a    string
+		b    string
+		want []Edit
+	}{
+		{
+			name: "replaced words",
+			a:    "Refunds take 5 days to arrive",
+			b:    "Refunds take 10 business days to arrive",
+			want: []Edit{
+				{Op: Equal, Text: "Refunds take"},
+				{Op: Delete, Text: "5"},
+				{Op: Insert, Text: "10 business"},
+				{Op: Equal, Text: "days to arrive"},
+			},
+		},
+		{
+			name: "appended words",
+			a:    "Open from 9",
+			b:    "Open from 9 to 5",
+			want: []Edit{
+				{Op: Equal, Text: "Open from 9"},
+				{Op: Insert, Text: "to 5"},
+			},
+		},
+		{
+			name: "same text",
+			a:    "Open  from 9",
+			b:    "Open from 9",
+			want: []Edit{{Op: Equal, Text: "Open from 9"}},
+		},
+		{
+			name: "empty texts",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, Diff(tt.a, tt.b)); diff != "" {
+				t.Errorf("unexpected edits mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -11,10 +11,18 @@ type ID []byte
 
 var Generator func() string
 
+// Clock returns the current time. Replace it to control time in tests.
+var Clock = time.Now
+
 func (id ID) String() string {
 	return base32.HexEncoding.EncodeToString(id)
 }
 
+// ParseID returns the ID represented by s, as returned by ID.String.
+func ParseID(s string) (ID, error) {
+	return base32.HexEncoding.DecodeString(s)
+}
+
 func NextID() ID {
 	return ID(Generator())
 }
@@ -27,3 +27,37 @@ func TestIDString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseID(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		in      string
+		want    ID
+		wantErr bool
+	}{
+		{
+			name: "parse id string",
+			in:   ID("test_id").String(),
+			want: ID("test_id"),
+		},
+		{
+			name:    "parse invalid id",
+			in:      "not an id",
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseID(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got = %v, want nil", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected id mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}